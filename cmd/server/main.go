@@ -1,76 +1,401 @@
 package main
 
 import (
+	"context"
+	"errors"
 	"fmt"
-	"log"
+	"log/slog"
 	"net"
+	"net/http"
+	"os"
+	"sync/atomic"
+	"time"
 
+	"github.com/paudelanil/grpc-crud/internal/accountnumber"
+	"github.com/paudelanil/grpc-crud/internal/config"
 	"github.com/paudelanil/grpc-crud/internal/handler"
+	"github.com/paudelanil/grpc-crud/internal/healthcheck"
+	"github.com/paudelanil/grpc-crud/internal/lifecycle"
+	"github.com/paudelanil/grpc-crud/internal/logging"
+	"github.com/paudelanil/grpc-crud/internal/metrics"
 	"github.com/paudelanil/grpc-crud/internal/middleware"
+	"github.com/paudelanil/grpc-crud/internal/money"
+	"github.com/paudelanil/grpc-crud/internal/pagetoken"
+	"github.com/paudelanil/grpc-crud/internal/reporting"
 	"github.com/paudelanil/grpc-crud/internal/repository"
 	"github.com/paudelanil/grpc-crud/internal/service"
+	"github.com/paudelanil/grpc-crud/internal/tracing"
+	"github.com/paudelanil/grpc-crud/internal/validation"
 	"github.com/paudelanil/grpc-crud/models"
 	pb "github.com/paudelanil/grpc-crud/pb"
+	"go.opentelemetry.io/contrib/instrumentation/google.golang.org/grpc/otelgrpc"
 	"google.golang.org/grpc"
+	"google.golang.org/grpc/health"
+	healthpb "google.golang.org/grpc/health/grpc_health_v1"
 	"google.golang.org/grpc/reflection"
 	"gorm.io/driver/postgres"
 	"gorm.io/gorm"
 )
 
+// maintenanceInterval is how often expired sessions and idempotency keys are purged
+const maintenanceInterval = time.Hour
+
 func main() {
 
-	dsn := "host=localhost user=postgres password=pass dbname=grpc_crud port=5432 sslmode=disable"
+	cfg, err := config.Load(os.Args[1:])
+	if err != nil {
+		fatal("failed to load configuration", err)
+	}
 
-	db, err := gorm.Open(postgres.Open(dsn), &gorm.Config{
+	logger, err := logging.New(os.Stdout, cfg.Log.Level, cfg.Log.Format)
+	if err != nil {
+		fatal("failed to set up logging", err)
+	}
+	slog.SetDefault(logger)
+	logger.Info("configuration loaded", "config", cfg.String())
+
+	// Tracing is set up first so the database and gRPC instrumentation use it
+	tracer, err := tracing.Setup(context.Background(), tracing.Options{
+		ServiceName: cfg.Tracing.ServiceName,
+		Exporter:    cfg.Tracing.Exporter,
+		Endpoint:    cfg.Tracing.Endpoint,
+		Insecure:    cfg.Tracing.Insecure,
+		SampleRatio: cfg.Tracing.SampleRatio,
+	})
+	if err != nil {
+		fatal("failed to set up tracing", err)
+	}
+
+	db, err := gorm.Open(postgres.Open(cfg.Database.DSN), &gorm.Config{
 		DisableForeignKeyConstraintWhenMigrating: true,
 	})
 
 	if err != nil {
-		log.Fatal(err)
+		fatal("failed to open database", err)
 	}
 
-	// Auto Migrate all tables at once
-	if err := db.AutoMigrate(&models.Customer{}, &models.Account{}, &models.User{}); err != nil {
-		log.Fatalf("Failed to migrate: %v", err)
+	// Trace every query; bound values are left out of the spans
+	if err := db.Use(tracing.NewGormPlugin()); err != nil {
+		fatal("failed to enable query tracing", err)
+	}
+
+	// Configure the connection pool
+	sqlDB, err := db.DB()
+	if err != nil {
+		fatal("failed to access database pool", err)
+	}
+	sqlDB.SetMaxOpenConns(cfg.Database.MaxOpenConns)
+	sqlDB.SetMaxIdleConns(cfg.Database.MaxIdleConns)
+	sqlDB.SetConnMaxLifetime(cfg.Database.ConnMaxLifetime)
+	sqlDB.SetConnMaxIdleTime(cfg.Database.ConnMaxIdleTime)
+
+	// Shutdown hooks run in reverse order, so spans are flushed after the
+	// pool is closed
+	lc := lifecycle.New(cfg.Server.ShutdownTimeout)
+	lc.OnShutdown("tracer", tracer.Shutdown)
+	lc.OnShutdown("database pool", func(ctx context.Context) error {
+		return sqlDB.Close()
+	})
+
+	// Metrics; business events are discarded when metrics are disabled
+	var events service.IEventRecorder = service.NopEventRecorder{}
+	var serverMetrics *metrics.Metrics
+	if cfg.Metrics.Enabled {
+		serverMetrics = metrics.New()
+		serverMetrics.RegisterDBStats(sqlDB, "grpc_crud")
+		events = serverMetrics
+	}
+
+	// Error reporting for recovered panics
+	var reporter reporting.Reporter = reporting.NopReporter{}
+	if cfg.Reporting.File != "" {
+		fileReporter, err := reporting.NewFileReporter(cfg.Reporting.File)
+		if err != nil {
+			fatal("failed to set up error reporting", err)
+		}
+		lc.OnShutdown("error reporter", func(ctx context.Context) error {
+			return fileReporter.Close()
+		})
+		reporter = fileReporter
 	}
 
 	// Initialize Repositories
 	userRepo := repository.NewUserRepository(db)
 	customerRepo := repository.NewCustomerRepository(db)
 	accountRepo := repository.NewAccountRepository(db)
+	ledgerRepo := repository.NewLedgerRepository(db)
+	idempotencyRepo := repository.NewIdempotencyRepository(db)
+	sessionRepo := repository.NewSessionRepository(db)
+	productRepo := repository.NewProductRepository(db)
+	statementRepo := repository.NewStatementRepository(db)
 
 	// Initialize Services
-	jwtSecret := "your-secret-key-change-this-in-production" // TODO: Move to environment variable
-	authService := service.NewAuthService(userRepo, jwtSecret)
-	customerService := service.NewCustomerService(customerRepo)
-	accountService := service.NewAccountService(accountRepo, customerRepo)
+	authService := service.NewAuthService(userRepo, customerRepo, sessionRepo, cfg.Auth.JWTSecret, cfg.Auth.AccessTokenTTL, cfg.Auth.RefreshTokenTTL, events)
+	// List page tokens are signed with a key derived from the JWT secret
+	pageTokens := pagetoken.NewSigner(cfg.Auth.JWTSecret)
+
+	customerService := service.NewCustomerService(customerRepo, pageTokens, events)
+	ledgerService := service.NewLedgerService(ledgerRepo)
+	accountNumbers := accountnumber.NewGenerator(accountnumber.Config{
+		Branch:       cfg.Accounts.Branch,
+		IBANCountry:  cfg.Accounts.IBANCountry,
+		IBANBankCode: cfg.Accounts.IBANBankCode,
+	}, accountRepo)
+	accountService := service.NewAccountService(accountRepo, customerRepo, productRepo, ledgerService, accountNumbers, pageTokens, events)
+	productService := service.NewProductService(productRepo)
+	statementService := service.NewStatementService(accountRepo, customerRepo, statementRepo, pageTokens)
+	idempotencyService := service.NewIdempotencyService(idempotencyRepo, cfg.Interceptors.IdempotencyTTL, cfg.Interceptors.IdempotencyLease)
 
 	// Initialize Handlers
 	accountHandler := handler.NewAccountHandler(customerService, accountService)
 	authHandler := handler.NewAuthHandler(authService)
+	productHandler := handler.NewProductHandler(productService)
+	statementHandler := handler.NewStatementHandler(statementService)
 
 	// start gRPC server
-	lis, err := net.Listen("tcp", fmt.Sprintf("%s:%s", "localhost", "8090"))
+	lis, err := net.Listen("tcp", cfg.Address())
 	if err != nil {
-		log.Fatalf("failed to listen: %v", err)
+		fatal("failed to listen", err)
 	}
 
-	// Create gRPC server with interceptors
+	// Build the interceptor chains; authentication, authorization and
+	// request validation are always on. Streaming RPCs get the same chain
+	// minus idempotency, which only applies to unary mutations.
+	validator := validation.Requests()
+	var interceptors []grpc.UnaryServerInterceptor
+	var streamInterceptors []grpc.StreamServerInterceptor
+	if serverMetrics != nil {
+		interceptors = append(interceptors, middleware.MetricsInterceptor(serverMetrics)) // count every request
+		streamInterceptors = append(streamInterceptors, middleware.StreamMetricsInterceptor(serverMetrics))
+	}
+	if cfg.Interceptors.Logging {
+		interceptors = append(interceptors, middleware.LoggingInterceptor(logger)) // log all requests
+		streamInterceptors = append(streamInterceptors, middleware.StreamLoggingInterceptor(logger))
+	}
+	// Recovery runs after metrics and logging so they see the Internal
+	// error, and before everything else so their panics are caught too.
+	// Until migrations finish only health checks and reflection are served.
+	var panicCounter middleware.PanicCounter
+	if serverMetrics != nil {
+		panicCounter = serverMetrics
+	}
+	var migrated atomic.Bool
+	interceptors = append(interceptors,
+		middleware.ReadinessInterceptor(migrated.Load),         // hold requests back until the schema is migrated
+		middleware.RecoveryInterceptor(panicCounter, reporter), // turn panics into Internal errors
+		middleware.AuthInterceptor(authService),                // validate authentication
+		middleware.AuthorizationInterceptor(),                  // enforce role policy
+		middleware.ValidationInterceptor(validator),            // reject malformed requests
+	)
+	streamInterceptors = append(streamInterceptors,
+		middleware.StreamReadinessInterceptor(migrated.Load),
+		middleware.StreamRecoveryInterceptor(panicCounter, reporter),
+		middleware.StreamAuthInterceptor(authService),
+		middleware.StreamAuthorizationInterceptor(),
+		middleware.StreamValidationInterceptor(validator),
+	)
+	if cfg.Interceptors.Idempotency {
+		interceptors = append(interceptors, middleware.IdempotencyInterceptor(idempotencyService)) // replay retried mutations
+	}
+
+	// Create gRPC server with interceptors; the stats handler starts a span
+	// per RPC, continuing the caller's trace when one is propagated
 	grpcServer := grpc.NewServer(
-		grpc.ChainUnaryInterceptor(
-			middleware.LoggingInterceptor(),        // First: log all requests
-			middleware.AuthInterceptor(authService), // Second: validate authentication
-		),
+		grpc.StatsHandler(otelgrpc.NewServerHandler()),
+		grpc.ChainUnaryInterceptor(interceptors...),
+		grpc.ChainStreamInterceptor(streamInterceptors...),
 	)
 
 	// Register gRPC services
 	pb.RegisterAccountServiceServer(grpcServer, accountHandler)
 	pb.RegisterLoginServiceServer(grpcServer, authHandler)
+	pb.RegisterProductServiceServer(grpcServer, productHandler)
+	pb.RegisterAccountStatementServiceServer(grpcServer, statementHandler)
 
 	reflection.Register(grpcServer)
-	log.Println("gRPC server listening on port", "8090")
-	if err := grpcServer.Serve(lis); err != nil {
-		log.Fatalf("failed to serve: %v", err)
+
+	// Health reporting; every service is NOT_SERVING until migrations finish
+	healthServer := health.NewServer()
+	healthpb.RegisterHealthServer(grpcServer, healthServer)
+	healthChecker := healthcheck.NewChecker(
+		healthServer,
+		sqlDB,
+		[]string{pb.AccountService_ServiceDesc.ServiceName, pb.LoginService_ServiceDesc.ServiceName, pb.ProductService_ServiceDesc.ServiceName, pb.AccountStatementService_ServiceDesc.ServiceName},
+		cfg.Health.CheckInterval,
+		cfg.Health.CheckTimeout,
+	)
+
+	lc.OnShutdown("gRPC server", lifecycle.StopGracefully(grpcServer))
+	lc.OnShutdown("readiness", func(ctx context.Context) error {
+		healthChecker.SetReady(false)
+		return nil
+	})
+	go func() {
+		logger.Info("gRPC server listening", "address", cfg.Address())
+		if err := grpcServer.Serve(lis); err != nil {
+			lc.Fail(fmt.Errorf("failed to serve: %w", err))
+		}
+	}()
+
+	// Serve /metrics on its own HTTP listener
+	if serverMetrics != nil {
+		mux := http.NewServeMux()
+		mux.Handle("/metrics", serverMetrics.Handler())
+		metricsServer := &http.Server{
+			Addr:              cfg.Metrics.Address,
+			Handler:           mux,
+			ReadHeaderTimeout: 5 * time.Second,
+		}
+		lc.OnShutdown("metrics server", metricsServer.Shutdown)
+		go func() {
+			logger.Info("metrics server listening", "address", cfg.Metrics.Address)
+			if err := metricsServer.ListenAndServe(); err != nil && !errors.Is(err, http.ErrServerClosed) {
+				lc.Fail(fmt.Errorf("failed to serve metrics: %w", err))
+			}
+		}()
+	}
+
+	// Migrate while the health service reports NOT_SERVING and every other
+	// RPC is turned away with Unavailable
+	if err := migrate(db); err != nil {
+		lc.Fail(fmt.Errorf("failed to migrate: %w", err))
+	} else {
+		bootstrapAdmin(authService, cfg.Auth.BootstrapAdmin, logger)
+		migrated.Store(true)
+		healthChecker.SetReady(true)
+		lc.Go("health checker", healthChecker.Run)
+
+		// Background maintenance
+		lc.Go("session purge", lifecycle.Every(maintenanceInterval, func(ctx context.Context) {
+			if err := authService.PurgeExpiredSessions(ctx); err != nil {
+				logger.ErrorContext(ctx, "failed to purge expired sessions", "error", err)
+			}
+		}))
+		if cfg.Interceptors.Idempotency {
+			lc.Go("idempotency purge", lifecycle.Every(maintenanceInterval, func(ctx context.Context) {
+				if _, err := idempotencyService.PurgeExpired(ctx); err != nil {
+					logger.ErrorContext(ctx, "failed to purge expired idempotency keys", "error", err)
+				}
+			}))
+		}
+	}
+
+	if err := lc.Run(); err != nil {
+		fatal("shutdown finished with errors", err)
+	}
+	logger.Info("server stopped")
+}
+
+// fatal logs err with the default logger and exits
+func fatal(msg string, err error) {
+	slog.Error(msg, "error", err)
+	os.Exit(1)
+}
+
+// bootstrapAdmin promotes the configured user to admin. A missing user is
+// logged rather than fatal, since it may not have registered yet.
+func bootstrapAdmin(authService service.IAuthService, username string, logger *slog.Logger) {
+	if username == "" {
+		return
+	}
+
+	promoted, err := authService.PromoteAdmin(context.Background(), username)
+	var notFound *repository.NotFoundError
+	switch {
+	case errors.As(err, &notFound):
+		logger.Warn("bootstrap admin is not registered; register the user and restart", "username", username)
+	case err != nil:
+		logger.Error("failed to promote bootstrap admin", "username", username, "error", err)
+	case promoted:
+		logger.Info("promoted bootstrap admin", "username", username)
+	}
+}
+
+// migrate creates or updates the database schema
+func migrate(db *gorm.DB) error {
+	// Auto Migrate all tables at once
+	if err := db.AutoMigrate(&models.Customer{}, &models.Account{}, &models.User{}, &models.JournalEntry{}, &models.Posting{}, &models.IdempotencyRecord{}, &models.Session{}, &models.RevokedToken{}, &models.AccountProduct{}, &models.ProductCurrency{}, &models.AccountStatusChange{}); err != nil {
+		return err
+	}
+	if err := db.Exec(fmt.Sprintf("CREATE SEQUENCE IF NOT EXISTS %s MAXVALUE 999999999", repository.AccountSerialSequence)).Error; err != nil {
+		return err
+	}
+	if err := migrateLegacyAmounts(db); err != nil {
+		return err
+	}
+	return seedProducts(db)
+}
+
+// seedProducts fills an empty product catalog with the default products
+func seedProducts(db *gorm.DB) error {
+	var count int64
+	if err := db.Model(&models.AccountProduct{}).Count(&count).Error; err != nil {
+		return err
+	}
+	if count > 0 {
+		return nil
+	}
+
+	products := models.DefaultProducts()
+	return db.Create(&products).Error
+}
+
+// migrateLegacyAmounts moves amounts out of the numeric(18,2) columns used
+// before money was stored in minor units. The amounts are read as decimal
+// text and rounded to the minor units of their currency, which AutoMigrate
+// has set to NPR for rows of that time.
+func migrateLegacyAmounts(db *gorm.DB) error {
+	migrator := db.Migrator()
+
+	if migrator.HasColumn(&models.Account{}, "balance") {
+		err := convertLegacyAmounts(db,
+			"SELECT account_id AS id, currency, balance::text AS amount FROM accounts WHERE balance_minor = 0 AND balance <> 0",
+			"UPDATE accounts SET balance_minor = ? WHERE account_id = ?")
+		if err != nil {
+			return err
+		}
+		if err := migrator.DropColumn(&models.Account{}, "balance"); err != nil {
+			return err
+		}
 	}
 
+	if migrator.HasColumn(&models.Posting{}, "amount") {
+		err := convertLegacyAmounts(db,
+			"SELECT p.posting_id AS id, e.currency, p.amount::text AS amount FROM postings p JOIN journal_entries e ON e.entry_id = p.entry_id WHERE p.amount_minor = 0 AND p.amount <> 0",
+			"UPDATE postings SET amount_minor = ? WHERE posting_id = ?")
+		if err != nil {
+			return err
+		}
+		if err := migrator.DropColumn(&models.Posting{}, "amount"); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// convertLegacyAmounts converts the rows selected by query, each an id, a
+// currency and a decimal amount, to minor units and stores them with update
+func convertLegacyAmounts(db *gorm.DB, query, update string) error {
+	var rows []struct {
+		ID       string
+		Currency string
+		Amount   string
+	}
+	if err := db.Raw(query).Scan(&rows).Error; err != nil {
+		return err
+	}
+
+	return db.Transaction(func(tx *gorm.DB) error {
+		for _, row := range rows {
+			units, err := money.ParseDecimal(row.Currency, row.Amount)
+			if err != nil {
+				return fmt.Errorf("failed to convert legacy amount of %s: %w", row.ID, err)
+			}
+			if err := tx.Exec(update, units, row.ID).Error; err != nil {
+				return err
+			}
+		}
+		return nil
+	})
 }
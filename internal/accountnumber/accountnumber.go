@@ -0,0 +1,247 @@
+// Package accountnumber issues and checks account numbers.
+//
+// An account number has 16 digits: a 3-digit branch code, the 2-digit
+// number prefix of the account's product, a 9-digit serial taken from a
+// database sequence and 2 ISO 7064 MOD 97-10 check digits, the scheme IBANs
+// use. A number is valid when, read as an integer, it leaves a remainder of
+// 1 when divided by 97, which catches every single-digit error and almost
+// every transposition.
+//
+// Accounts opened before this scheme keep their legacy numbers, the
+// customer's phone number and the Unix time of opening joined by a hyphen.
+package accountnumber
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"regexp"
+	"strings"
+)
+
+// Lengths of the parts of an account number
+const (
+	BranchLength = 3
+	PrefixLength = 2
+	SerialLength = 9
+	Length       = BranchLength + PrefixLength + SerialLength + 2
+)
+
+// maxSerial is the largest serial that fits in an account number
+const maxSerial = 999_999_999
+
+// ErrInvalid is returned for account numbers that are malformed or fail
+// their check digits
+var ErrInvalid = errors.New("invalid account number")
+
+// Sequence hands out the serial part of new account numbers. Serials must
+// never repeat, which makes the numbers built from them unique.
+type Sequence interface {
+	NextAccountSerial(ctx context.Context) (int64, error)
+}
+
+// Generator issues new account numbers
+type Generator interface {
+	// Generate returns a new account number for a product with the given
+	// number prefix
+	Generate(ctx context.Context, productPrefix string) (string, error)
+	// IBAN returns the IBAN of an account number, or "" when IBANs are not
+	// configured
+	IBAN(number string) string
+}
+
+// Config holds the bank's numbering settings
+type Config struct {
+	Branch       string // 3-digit branch code at the start of every number
+	IBANCountry  string // ISO 3166 country code; empty disables IBANs
+	IBANBankCode string // bank identifier placed before the account number in IBANs
+}
+
+// SequenceGenerator builds account numbers from a database sequence
+type SequenceGenerator struct {
+	config   Config
+	sequence Sequence
+}
+
+// NewGenerator creates a Generator that takes its serials from sequence
+func NewGenerator(config Config, sequence Sequence) Generator {
+	return &SequenceGenerator{config: config, sequence: sequence}
+}
+
+// Generate returns a new account number
+func (g *SequenceGenerator) Generate(ctx context.Context, productPrefix string) (string, error) {
+	if err := ValidatePrefix(productPrefix); err != nil {
+		return "", err
+	}
+
+	serial, err := g.sequence.NextAccountSerial(ctx)
+	if err != nil {
+		return "", fmt.Errorf("failed to allocate account serial: %w", err)
+	}
+	if serial <= 0 || serial > maxSerial {
+		return "", fmt.Errorf("account serial %d is out of range", serial)
+	}
+
+	return Build(g.config.Branch, productPrefix, serial)
+}
+
+// IBAN returns the IBAN of an account number in its electronic form
+func (g *SequenceGenerator) IBAN(number string) string {
+	if g.config.IBANCountry == "" {
+		return ""
+	}
+	iban, err := IBAN(g.config.IBANCountry, g.config.IBANBankCode, number)
+	if err != nil {
+		return ""
+	}
+	return iban
+}
+
+// Build assembles an account number from its parts and adds the check digits
+func Build(branch, productPrefix string, serial int64) (string, error) {
+	if err := ValidateBranch(branch); err != nil {
+		return "", err
+	}
+	if err := ValidatePrefix(productPrefix); err != nil {
+		return "", err
+	}
+	if serial < 0 || serial > maxSerial {
+		return "", fmt.Errorf("account serial %d is out of range", serial)
+	}
+
+	body := fmt.Sprintf("%s%s%0*d", branch, productPrefix, SerialLength, serial)
+	return body + checkDigits(body), nil
+}
+
+// ValidatePrefix checks a product number prefix
+func ValidatePrefix(prefix string) error {
+	if !isDigits(prefix, PrefixLength) {
+		return fmt.Errorf("product number prefix must be %d digits, got %q", PrefixLength, prefix)
+	}
+	return nil
+}
+
+// ValidateBranch checks a branch code
+func ValidateBranch(branch string) error {
+	if !isDigits(branch, BranchLength) {
+		return fmt.Errorf("branch code must be %d digits, got %q", BranchLength, branch)
+	}
+	return nil
+}
+
+// Normalize removes the spaces and hyphens people use to group digits
+func Normalize(number string) string {
+	return strings.Map(func(r rune) rune {
+		if r == ' ' || r == '-' {
+			return -1
+		}
+		return r
+	}, number)
+}
+
+// Validate checks the length, characters and check digits of an account
+// number. Spaces and hyphens are ignored.
+func Validate(number string) error {
+	number = Normalize(number)
+	if !isDigits(number, Length) || mod97(number) != 1 {
+		return ErrInvalid
+	}
+	return nil
+}
+
+// legacyNumber matches the phone-timestamp numbers issued before check
+// digits, e.g. 9841234567-1700000000
+var legacyNumber = regexp.MustCompile(`^\S+-[0-9]{9,10}$`)
+
+// IsLegacy reports whether number has the legacy phone-timestamp form. Legacy
+// numbers have no check digits and are looked up exactly as given.
+func IsLegacy(number string) bool {
+	return legacyNumber.MatchString(number)
+}
+
+// IBAN builds the IBAN of an account number: the country code, two check
+// digits and the bank code followed by the account number
+func IBAN(country, bankCode, number string) (string, error) {
+	country = strings.ToUpper(country)
+	if len(country) != 2 || !isLetters(country) {
+		return "", fmt.Errorf("IBAN country must be 2 letters, got %q", country)
+	}
+	if err := Validate(number); err != nil {
+		return "", err
+	}
+
+	bban := strings.ToUpper(bankCode) + Normalize(number)
+	if !isAlphanumeric(bban) {
+		return "", fmt.Errorf("IBAN bank code must be letters and digits, got %q", bankCode)
+	}
+	if len(bban) > 30 {
+		return "", fmt.Errorf("IBAN bank code %q is too long", bankCode)
+	}
+
+	check := 98 - mod97(bban+country+"00")
+	return fmt.Sprintf("%s%02d%s", country, check, bban), nil
+}
+
+// FormatIBAN returns an IBAN in its print form, in groups of four
+// characters, e.g. NP09 ABCD 0011 0000 0000 4204
+func FormatIBAN(iban string) string {
+	var b strings.Builder
+	for i, r := range iban {
+		if i > 0 && i%4 == 0 {
+			b.WriteByte(' ')
+		}
+		b.WriteRune(r)
+	}
+	return b.String()
+}
+
+// checkDigits returns the two ISO 7064 MOD 97-10 check digits of body
+func checkDigits(body string) string {
+	return fmt.Sprintf("%02d", 98-mod97(body+"00"))
+}
+
+// mod97 returns s mod 97, reading letters as 10 to 35 as IBANs do.
+// s must be ASCII letters and digits.
+func mod97(s string) int {
+	r := 0
+	for i := 0; i < len(s); i++ {
+		c := s[i]
+		switch {
+		case c >= '0' && c <= '9':
+			r = (r*10 + int(c-'0')) % 97
+		case c >= 'A' && c <= 'Z':
+			r = (r*100 + int(c-'A') + 10) % 97
+		}
+	}
+	return r
+}
+
+func isDigits(s string, n int) bool {
+	if len(s) != n {
+		return false
+	}
+	for i := 0; i < len(s); i++ {
+		if s[i] < '0' || s[i] > '9' {
+			return false
+		}
+	}
+	return true
+}
+
+func isLetters(s string) bool {
+	for i := 0; i < len(s); i++ {
+		if s[i] < 'A' || s[i] > 'Z' {
+			return false
+		}
+	}
+	return true
+}
+
+func isAlphanumeric(s string) bool {
+	for i := 0; i < len(s); i++ {
+		if (s[i] < '0' || s[i] > '9') && (s[i] < 'A' || s[i] > 'Z') {
+			return false
+		}
+	}
+	return true
+}
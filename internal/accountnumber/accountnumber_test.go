@@ -0,0 +1,176 @@
+package accountnumber
+
+import (
+	"math/big"
+	"strings"
+	"testing"
+)
+
+func TestBuild(t *testing.T) {
+	tests := []struct {
+		branch string
+		prefix string
+		serial int64
+		want   string
+	}{
+		{"001", "10", 42, "0011000000004204"},
+		{"001", "20", 1, "0012000000000177"},
+		{"001", "01", 0, "0010100000000078"},
+		{"999", "99", maxSerial, "9999999999999939"},
+	}
+
+	for _, tt := range tests {
+		got, err := Build(tt.branch, tt.prefix, tt.serial)
+		if err != nil {
+			t.Errorf("Build(%q, %q, %d): %v", tt.branch, tt.prefix, tt.serial, err)
+			continue
+		}
+		if got != tt.want {
+			t.Errorf("Build(%q, %q, %d) = %q, want %q", tt.branch, tt.prefix, tt.serial, got, tt.want)
+		}
+		if err := Validate(got); err != nil {
+			t.Errorf("Validate(%q): %v", got, err)
+		}
+	}
+}
+
+func TestBuildRejects(t *testing.T) {
+	tests := []struct {
+		branch string
+		prefix string
+		serial int64
+	}{
+		{"01", "10", 1},
+		{"0a1", "10", 1},
+		{"001", "1", 1},
+		{"001", "10", -1},
+		{"001", "10", maxSerial + 1},
+	}
+
+	for _, tt := range tests {
+		if got, err := Build(tt.branch, tt.prefix, tt.serial); err == nil {
+			t.Errorf("Build(%q, %q, %d) = %q, want an error", tt.branch, tt.prefix, tt.serial, got)
+		}
+	}
+}
+
+func TestValidate(t *testing.T) {
+	tests := []struct {
+		number string
+		valid  bool
+	}{
+		{"0011000000004204", true},
+		{"0011 0000 0000 4204", true},
+		{"0011-0000-0000-4204", true},
+
+		{"0011000000004205", false}, // wrong check digit
+		{"0011000000005204", false}, // single-digit error
+		{"1011000000004204", false}, // single-digit error in the branch
+		{"0101000000004204", false}, // transposition
+		{"0011000000004024", false}, // transposition across the check digits
+		{"001100000000420", false},  // too short
+		{"00110000000042040", false},
+		{"001100000000420a", false},
+		{"", false},
+	}
+
+	for _, tt := range tests {
+		err := Validate(tt.number)
+		if (err == nil) != tt.valid {
+			t.Errorf("Validate(%q) = %v, want valid: %v", tt.number, err, tt.valid)
+		}
+	}
+}
+
+func TestValidateCatchesSingleDigitErrors(t *testing.T) {
+	const number = "0011000000004204"
+	for i := 0; i < len(number); i++ {
+		for d := byte('0'); d <= '9'; d++ {
+			if d == number[i] {
+				continue
+			}
+			changed := number[:i] + string(d) + number[i+1:]
+			if Validate(changed) == nil {
+				t.Errorf("Validate(%q) accepted a single-digit error at position %d", changed, i)
+			}
+		}
+	}
+}
+
+func TestIsLegacy(t *testing.T) {
+	tests := []struct {
+		number string
+		legacy bool
+	}{
+		{"9841234567-1700000000", true},
+		{"+977-9841234567-1700000000", true},
+		{"0011000000004204", false},
+		{"0011-0000-0000-4204", false},
+		{"9841234567", false},
+		{"-1700000000", false},
+	}
+
+	for _, tt := range tests {
+		if got := IsLegacy(tt.number); got != tt.legacy {
+			t.Errorf("IsLegacy(%q) = %v, want %v", tt.number, got, tt.legacy)
+		}
+	}
+}
+
+func TestIBAN(t *testing.T) {
+	iban, err := IBAN("np", "ABCD", "0011 0000 0000 4204")
+	if err != nil {
+		t.Fatalf("IBAN: %v", err)
+	}
+	if want := "NP09ABCD0011000000004204"; iban != want {
+		t.Fatalf("IBAN = %q, want %q", iban, want)
+	}
+	if !ibanValid(iban) {
+		t.Fatalf("IBAN %q fails the ISO 13616 check", iban)
+	}
+	if got, want := FormatIBAN(iban), "NP09 ABCD 0011 0000 0000 4204"; got != want {
+		t.Fatalf("FormatIBAN = %q, want %q", got, want)
+	}
+
+	// A published example IBAN checks out with the same arithmetic
+	if !ibanValid("GB82WEST12345698765432") {
+		t.Fatal("the example IBAN GB82WEST12345698765432 fails the check")
+	}
+}
+
+func TestIBANRejects(t *testing.T) {
+	tests := []struct {
+		country  string
+		bankCode string
+		number   string
+	}{
+		{"NPL", "ABCD", "0011000000004204"},
+		{"N1", "ABCD", "0011000000004204"},
+		{"NP", "AB-D", "0011000000004204"},
+		{"NP", strings.Repeat("A", 15), "0011000000004204"},
+		{"NP", "ABCD", "0011000000004205"},
+	}
+
+	for _, tt := range tests {
+		if got, err := IBAN(tt.country, tt.bankCode, tt.number); err == nil {
+			t.Errorf("IBAN(%q, %q, %q) = %q, want an error", tt.country, tt.bankCode, tt.number, got)
+		}
+	}
+}
+
+// ibanValid checks an IBAN the way ISO 13616 describes, independently of
+// mod97: move the first four characters to the end, replace letters with
+// 10 to 35 and take the result mod 97
+func ibanValid(iban string) bool {
+	rearranged := iban[4:] + iban[:4]
+	var digits strings.Builder
+	for _, r := range rearranged {
+		if r >= 'A' && r <= 'Z' {
+			digits.WriteString(big.NewInt(int64(r-'A') + 10).String())
+		} else {
+			digits.WriteRune(r)
+		}
+	}
+	n, ok := new(big.Int).SetString(digits.String(), 10)
+	return ok && new(big.Int).Mod(n, big.NewInt(97)).Int64() == 1
+}
@@ -0,0 +1,473 @@
+package config
+
+import (
+	"errors"
+	"flag"
+	"fmt"
+	"net/url"
+	"os"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/paudelanil/grpc-crud/internal/accountnumber"
+	"gopkg.in/yaml.v3"
+)
+
+// Deployment environments
+const (
+	EnvDevelopment = "development"
+	EnvStaging     = "staging"
+	EnvProduction  = "production"
+)
+
+// developmentJWTSecret is only accepted in development
+const developmentJWTSecret = "your-secret-key-change-this-in-production"
+
+// Config holds all server settings
+type Config struct {
+	Environment  string             `yaml:"environment"`
+	Server       ServerConfig       `yaml:"server"`
+	Log          LogConfig          `yaml:"log"`
+	Database     DatabaseConfig     `yaml:"database"`
+	Auth         AuthConfig         `yaml:"auth"`
+	Interceptors InterceptorsConfig `yaml:"interceptors"`
+	Health       HealthConfig       `yaml:"health"`
+	Metrics      MetricsConfig      `yaml:"metrics"`
+	Tracing      TracingConfig      `yaml:"tracing"`
+	Reporting    ReportingConfig    `yaml:"reporting"`
+	Accounts     AccountsConfig     `yaml:"accounts"`
+}
+
+// ServerConfig holds the gRPC listener settings
+type ServerConfig struct {
+	Host            string        `yaml:"host"`
+	Port            int           `yaml:"port"`
+	ShutdownTimeout time.Duration `yaml:"shutdown_timeout"` // time allowed for in-flight RPCs to finish
+}
+
+// LogConfig holds the log output settings
+type LogConfig struct {
+	Level  string `yaml:"level"`  // debug, info, warn or error
+	Format string `yaml:"format"` // json or text
+}
+
+// DatabaseConfig holds the Postgres connection and pool settings
+type DatabaseConfig struct {
+	DSN             string        `yaml:"dsn"`
+	MaxOpenConns    int           `yaml:"max_open_conns"`
+	MaxIdleConns    int           `yaml:"max_idle_conns"`
+	ConnMaxLifetime time.Duration `yaml:"conn_max_lifetime"`
+	ConnMaxIdleTime time.Duration `yaml:"conn_max_idle_time"`
+}
+
+// AuthConfig holds the token settings
+type AuthConfig struct {
+	JWTSecret       string        `yaml:"jwt_secret"`
+	AccessTokenTTL  time.Duration `yaml:"access_token_ttl"`
+	RefreshTokenTTL time.Duration `yaml:"refresh_token_ttl"`
+	BootstrapAdmin  string        `yaml:"bootstrap_admin"` // username promoted to admin at startup; empty promotes no one
+}
+
+// InterceptorsConfig switches optional interceptors on and off.
+// Authentication and authorization are always on.
+type InterceptorsConfig struct {
+	Logging          bool          `yaml:"logging"`
+	Idempotency      bool          `yaml:"idempotency"`
+	IdempotencyTTL   time.Duration `yaml:"idempotency_ttl"`
+	IdempotencyLease time.Duration `yaml:"idempotency_lease"` // how long a key stays reserved by a request that has not finished
+}
+
+// HealthConfig holds the database health check settings
+type HealthConfig struct {
+	CheckInterval time.Duration `yaml:"check_interval"`
+	CheckTimeout  time.Duration `yaml:"check_timeout"`
+}
+
+// MetricsConfig holds the Prometheus endpoint settings
+type MetricsConfig struct {
+	Enabled bool   `yaml:"enabled"`
+	Address string `yaml:"address"` // host:port of the HTTP listener serving /metrics
+}
+
+// TracingConfig holds the OpenTelemetry span export settings
+type TracingConfig struct {
+	ServiceName string  `yaml:"service_name"`
+	Exporter    string  `yaml:"exporter"` // none, otlp, stdout or memory
+	Endpoint    string  `yaml:"endpoint"` // OTLP collector host:port
+	Insecure    bool    `yaml:"insecure"` // send OTLP without TLS
+	SampleRatio float64 `yaml:"sample_ratio"`
+}
+
+// ReportingConfig holds the error reporting settings
+type ReportingConfig struct {
+	File string `yaml:"file"` // append recovered panics to this file as JSON lines; empty disables reporting
+}
+
+// AccountsConfig holds the account numbering settings
+type AccountsConfig struct {
+	Branch       string `yaml:"branch"`         // 3-digit branch code at the start of new account numbers
+	IBANCountry  string `yaml:"iban_country"`   // ISO 3166 country code of IBANs; empty disables IBANs
+	IBANBankCode string `yaml:"iban_bank_code"` // bank identifier placed before the account number in IBANs
+}
+
+// Default returns the settings used when nothing else is configured
+func Default() *Config {
+	return &Config{
+		Environment: EnvDevelopment,
+		Server: ServerConfig{
+			Host:            "localhost",
+			Port:            8090,
+			ShutdownTimeout: 30 * time.Second,
+		},
+		Log: LogConfig{
+			Level:  "info",
+			Format: "json",
+		},
+		Database: DatabaseConfig{
+			DSN:             "host=localhost user=postgres password=pass dbname=grpc_crud port=5432 sslmode=disable",
+			MaxOpenConns:    25,
+			MaxIdleConns:    5,
+			ConnMaxLifetime: 30 * time.Minute,
+			ConnMaxIdleTime: 5 * time.Minute,
+		},
+		Auth: AuthConfig{
+			JWTSecret:       developmentJWTSecret,
+			AccessTokenTTL:  15 * time.Minute,
+			RefreshTokenTTL: 7 * 24 * time.Hour,
+		},
+		Interceptors: InterceptorsConfig{
+			Logging:          true,
+			Idempotency:      true,
+			IdempotencyTTL:   24 * time.Hour,
+			IdempotencyLease: time.Minute,
+		},
+		Health: HealthConfig{
+			CheckInterval: 10 * time.Second,
+			CheckTimeout:  2 * time.Second,
+		},
+		Metrics: MetricsConfig{
+			Enabled: true,
+			Address: "localhost:9090",
+		},
+		Tracing: TracingConfig{
+			ServiceName: "grpc-crud",
+			Exporter:    "none",
+			SampleRatio: 1,
+		},
+		Accounts: AccountsConfig{
+			Branch: "001",
+		},
+	}
+}
+
+// setting binds one field of Config to a command-line flag and an
+// environment variable
+type setting struct {
+	flag  string
+	env   string
+	usage string
+	field func(c *Config) interface{}
+}
+
+// settings lists every option that can be set from flags or the environment
+var settings = []setting{
+	{"env", "GRPC_CRUD_ENV", "deployment environment: development, staging or production", func(c *Config) interface{} { return &c.Environment }},
+	{"host", "GRPC_CRUD_HOST", "gRPC listen host", func(c *Config) interface{} { return &c.Server.Host }},
+	{"port", "GRPC_CRUD_PORT", "gRPC listen port", func(c *Config) interface{} { return &c.Server.Port }},
+	{"shutdown-timeout", "GRPC_CRUD_SHUTDOWN_TIMEOUT", "time allowed for in-flight RPCs to finish on shutdown", func(c *Config) interface{} { return &c.Server.ShutdownTimeout }},
+	{"log-level", "GRPC_CRUD_LOG_LEVEL", "minimum log level: debug, info, warn or error", func(c *Config) interface{} { return &c.Log.Level }},
+	{"log-format", "GRPC_CRUD_LOG_FORMAT", "log output format: json or text", func(c *Config) interface{} { return &c.Log.Format }},
+	{"db-dsn", "GRPC_CRUD_DB_DSN", "Postgres DSN", func(c *Config) interface{} { return &c.Database.DSN }},
+	{"db-max-open-conns", "GRPC_CRUD_DB_MAX_OPEN_CONNS", "maximum open database connections", func(c *Config) interface{} { return &c.Database.MaxOpenConns }},
+	{"db-max-idle-conns", "GRPC_CRUD_DB_MAX_IDLE_CONNS", "maximum idle database connections", func(c *Config) interface{} { return &c.Database.MaxIdleConns }},
+	{"db-conn-max-lifetime", "GRPC_CRUD_DB_CONN_MAX_LIFETIME", "maximum lifetime of a database connection", func(c *Config) interface{} { return &c.Database.ConnMaxLifetime }},
+	{"db-conn-max-idle-time", "GRPC_CRUD_DB_CONN_MAX_IDLE_TIME", "maximum idle time of a database connection", func(c *Config) interface{} { return &c.Database.ConnMaxIdleTime }},
+	{"jwt-secret", "GRPC_CRUD_JWT_SECRET", "secret used to sign tokens", func(c *Config) interface{} { return &c.Auth.JWTSecret }},
+	{"access-token-ttl", "GRPC_CRUD_ACCESS_TOKEN_TTL", "lifetime of access tokens", func(c *Config) interface{} { return &c.Auth.AccessTokenTTL }},
+	{"refresh-token-ttl", "GRPC_CRUD_REFRESH_TOKEN_TTL", "lifetime of refresh tokens", func(c *Config) interface{} { return &c.Auth.RefreshTokenTTL }},
+	{"bootstrap-admin", "GRPC_CRUD_BOOTSTRAP_ADMIN", "username promoted to admin at startup", func(c *Config) interface{} { return &c.Auth.BootstrapAdmin }},
+	{"logging", "GRPC_CRUD_LOGGING", "enable the request logging interceptor", func(c *Config) interface{} { return &c.Interceptors.Logging }},
+	{"idempotency", "GRPC_CRUD_IDEMPOTENCY", "enable the idempotency interceptor", func(c *Config) interface{} { return &c.Interceptors.Idempotency }},
+	{"idempotency-ttl", "GRPC_CRUD_IDEMPOTENCY_TTL", "how long idempotency keys are remembered", func(c *Config) interface{} { return &c.Interceptors.IdempotencyTTL }},
+	{"idempotency-lease", "GRPC_CRUD_IDEMPOTENCY_LEASE", "how long an unfinished request holds its idempotency key", func(c *Config) interface{} { return &c.Interceptors.IdempotencyLease }},
+	{"health-check-interval", "GRPC_CRUD_HEALTH_CHECK_INTERVAL", "how often the database is pinged for health checks", func(c *Config) interface{} { return &c.Health.CheckInterval }},
+	{"health-check-timeout", "GRPC_CRUD_HEALTH_CHECK_TIMEOUT", "timeout of a health check database ping", func(c *Config) interface{} { return &c.Health.CheckTimeout }},
+	{"metrics", "GRPC_CRUD_METRICS", "enable the metrics interceptor and /metrics endpoint", func(c *Config) interface{} { return &c.Metrics.Enabled }},
+	{"metrics-address", "GRPC_CRUD_METRICS_ADDRESS", "listen address of the /metrics endpoint", func(c *Config) interface{} { return &c.Metrics.Address }},
+	{"tracing-service-name", "GRPC_CRUD_TRACING_SERVICE_NAME", "service name reported on spans", func(c *Config) interface{} { return &c.Tracing.ServiceName }},
+	{"tracing-exporter", "GRPC_CRUD_TRACING_EXPORTER", "span exporter: none, otlp, stdout or memory", func(c *Config) interface{} { return &c.Tracing.Exporter }},
+	{"tracing-endpoint", "GRPC_CRUD_TRACING_ENDPOINT", "OTLP collector host:port", func(c *Config) interface{} { return &c.Tracing.Endpoint }},
+	{"tracing-insecure", "GRPC_CRUD_TRACING_INSECURE", "send OTLP spans without TLS", func(c *Config) interface{} { return &c.Tracing.Insecure }},
+	{"error-report-file", "GRPC_CRUD_ERROR_REPORT_FILE", "file that recovered panics are reported to", func(c *Config) interface{} { return &c.Reporting.File }},
+	{"account-branch", "GRPC_CRUD_ACCOUNT_BRANCH", "3-digit branch code of new account numbers", func(c *Config) interface{} { return &c.Accounts.Branch }},
+	{"iban-country", "GRPC_CRUD_IBAN_COUNTRY", "country code of IBANs; empty disables IBANs", func(c *Config) interface{} { return &c.Accounts.IBANCountry }},
+	{"iban-bank-code", "GRPC_CRUD_IBAN_BANK_CODE", "bank identifier of IBANs", func(c *Config) interface{} { return &c.Accounts.IBANBankCode }},
+	{"tracing-sample-ratio", "GRPC_CRUD_TRACING_SAMPLE_RATIO", "fraction of new traces that are sampled, from 0 to 1", func(c *Config) interface{} { return &c.Tracing.SampleRatio }},
+}
+
+// Load builds the configuration from, in increasing order of precedence,
+// the defaults, an optional YAML file, environment variables and
+// command-line flags. The file is named by -config or GRPC_CRUD_CONFIG.
+func Load(args []string) (*Config, error) {
+	fs := flag.NewFlagSet("server", flag.ContinueOnError)
+	configPath := fs.String("config", os.Getenv("GRPC_CRUD_CONFIG"), "path to a YAML config file")
+
+	defaults := Default()
+	flags := make(map[string]*flagValue, len(settings))
+	for _, s := range settings {
+		_, isBool := s.field(defaults).(*bool)
+		flags[s.flag] = &flagValue{isBool: isBool}
+		fs.Var(flags[s.flag], s.flag, fmt.Sprintf("%s (env %s)", s.usage, s.env))
+	}
+
+	if err := fs.Parse(args); err != nil {
+		return nil, err
+	}
+
+	cfg := defaults
+	if *configPath != "" {
+		if err := loadFile(cfg, *configPath); err != nil {
+			return nil, err
+		}
+	}
+
+	for _, s := range settings {
+		value, ok := os.LookupEnv(s.env)
+		if !ok {
+			continue
+		}
+		if err := setField(s.field(cfg), value); err != nil {
+			return nil, fmt.Errorf("invalid %s: %w", s.env, err)
+		}
+	}
+
+	for _, s := range settings {
+		f := flags[s.flag]
+		if !f.set {
+			continue
+		}
+		if err := setField(s.field(cfg), f.value); err != nil {
+			return nil, fmt.Errorf("invalid -%s: %w", s.flag, err)
+		}
+	}
+
+	if err := cfg.Validate(); err != nil {
+		return nil, err
+	}
+
+	return cfg, nil
+}
+
+// loadFile overlays the settings found in a YAML file onto cfg
+func loadFile(cfg *Config, path string) error {
+	f, err := os.Open(path)
+	if err != nil {
+		return fmt.Errorf("failed to open config file: %w", err)
+	}
+	defer f.Close()
+
+	decoder := yaml.NewDecoder(f)
+	decoder.KnownFields(true)
+	if err := decoder.Decode(cfg); err != nil {
+		return fmt.Errorf("failed to parse config file %s: %w", path, err)
+	}
+	return nil
+}
+
+// Validate checks that the settings are usable
+func (c *Config) Validate() error {
+	var problems []string
+
+	switch c.Environment {
+	case EnvDevelopment, EnvStaging, EnvProduction:
+	default:
+		problems = append(problems, fmt.Sprintf("environment must be development, staging or production, got %q", c.Environment))
+	}
+
+	if c.Server.Port <= 0 || c.Server.Port > 65535 {
+		problems = append(problems, "server port must be between 1 and 65535")
+	}
+	if c.Server.ShutdownTimeout <= 0 {
+		problems = append(problems, "shutdown timeout must be positive")
+	}
+
+	switch strings.ToLower(c.Log.Level) {
+	case "debug", "info", "warn", "error":
+	default:
+		problems = append(problems, fmt.Sprintf("log level must be debug, info, warn or error, got %q", c.Log.Level))
+	}
+	switch c.Log.Format {
+	case "json", "text":
+	default:
+		problems = append(problems, fmt.Sprintf("log format must be json or text, got %q", c.Log.Format))
+	}
+
+	if c.Database.DSN == "" {
+		problems = append(problems, "database DSN is required")
+	}
+	if c.Database.MaxOpenConns < 0 || c.Database.MaxIdleConns < 0 {
+		problems = append(problems, "database pool sizes must not be negative")
+	}
+	if c.Database.MaxOpenConns > 0 && c.Database.MaxIdleConns > c.Database.MaxOpenConns {
+		problems = append(problems, "database max idle connections must not exceed max open connections")
+	}
+
+	if c.Auth.JWTSecret == "" {
+		problems = append(problems, "JWT secret is required")
+	} else if c.Environment != EnvDevelopment {
+		if c.Auth.JWTSecret == developmentJWTSecret {
+			problems = append(problems, "the development JWT secret must not be used outside development")
+		}
+		if len(c.Auth.JWTSecret) < 32 {
+			problems = append(problems, "JWT secret must be at least 32 characters")
+		}
+	}
+	if c.Auth.AccessTokenTTL <= 0 || c.Auth.RefreshTokenTTL <= 0 {
+		problems = append(problems, "token TTLs must be positive")
+	} else if c.Auth.AccessTokenTTL >= c.Auth.RefreshTokenTTL {
+		problems = append(problems, "access token TTL must be shorter than refresh token TTL")
+	}
+
+	if c.Interceptors.Idempotency {
+		if c.Interceptors.IdempotencyTTL <= 0 || c.Interceptors.IdempotencyLease <= 0 {
+			problems = append(problems, "idempotency TTL and lease must be positive")
+		} else if c.Interceptors.IdempotencyLease > c.Interceptors.IdempotencyTTL {
+			problems = append(problems, "idempotency lease must not exceed the idempotency TTL")
+		}
+	}
+
+	if c.Health.CheckInterval <= 0 || c.Health.CheckTimeout <= 0 {
+		problems = append(problems, "health check interval and timeout must be positive")
+	}
+
+	if c.Metrics.Enabled && c.Metrics.Address == "" {
+		problems = append(problems, "metrics address is required when metrics are enabled")
+	}
+
+	switch c.Tracing.Exporter {
+	case "none", "otlp", "stdout":
+	case "memory":
+		// Spans are kept until shutdown, so memory only grows
+		if c.Environment != EnvDevelopment {
+			problems = append(problems, "the memory tracing exporter must not be used outside development")
+		}
+	default:
+		problems = append(problems, fmt.Sprintf("tracing exporter must be none, otlp, stdout or memory, got %q", c.Tracing.Exporter))
+	}
+	if c.Tracing.ServiceName == "" {
+		problems = append(problems, "tracing service name is required")
+	}
+	if c.Tracing.SampleRatio < 0 || c.Tracing.SampleRatio > 1 {
+		problems = append(problems, "tracing sample ratio must be between 0 and 1")
+	}
+
+	if err := accountnumber.ValidateBranch(c.Accounts.Branch); err != nil {
+		problems = append(problems, "account "+err.Error())
+	}
+	if c.Accounts.IBANCountry != "" {
+		if _, err := accountnumber.IBAN(c.Accounts.IBANCountry, c.Accounts.IBANBankCode, sampleAccountNumber); err != nil {
+			problems = append(problems, err.Error())
+		}
+		if c.Accounts.IBANBankCode == "" {
+			problems = append(problems, "IBAN bank code is required when an IBAN country is set")
+		}
+	}
+
+	if len(problems) > 0 {
+		return errors.New("invalid configuration: " + strings.Join(problems, "; "))
+	}
+	return nil
+}
+
+// sampleAccountNumber is a valid account number used to check IBAN settings
+const sampleAccountNumber = "0011000000004204"
+
+// Address returns the host:port the gRPC server listens on
+func (c *Config) Address() string {
+	return fmt.Sprintf("%s:%d", c.Server.Host, c.Server.Port)
+}
+
+// Redacted returns a copy of the configuration that is safe to log
+func (c *Config) Redacted() Config {
+	redacted := *c
+	redacted.Database.DSN = redactDSN(c.Database.DSN)
+	if redacted.Auth.JWTSecret != "" {
+		redacted.Auth.JWTSecret = "[REDACTED]"
+	}
+	return redacted
+}
+
+// String renders the redacted configuration
+func (c *Config) String() string {
+	redacted := c.Redacted()
+	out, err := yaml.Marshal(&redacted)
+	if err != nil {
+		return "<unprintable config>"
+	}
+	return string(out)
+}
+
+// dsnPassword matches the password of a key=value DSN
+var dsnPassword = regexp.MustCompile(`(?i)(password\s*=\s*)('[^']*'|\S+)`)
+
+// redactDSN hides the password in both URL and key=value DSNs
+func redactDSN(dsn string) string {
+	if u, err := url.Parse(dsn); err == nil && u.Scheme != "" && u.User != nil {
+		return u.Redacted()
+	}
+	return dsnPassword.ReplaceAllString(dsn, "${1}[REDACTED]")
+}
+
+// flagValue records the raw value of a flag so it can be applied after
+// the file and environment
+type flagValue struct {
+	value  string
+	set    bool
+	isBool bool
+}
+
+func (f *flagValue) String() string { return f.value }
+
+func (f *flagValue) Set(value string) error {
+	f.value, f.set = value, true
+	return nil
+}
+
+func (f *flagValue) IsBoolFlag() bool { return f.isBool }
+
+// setField parses value into the field pointer
+func setField(field interface{}, value string) error {
+	switch p := field.(type) {
+	case *string:
+		*p = value
+	case *int:
+		v, err := strconv.Atoi(value)
+		if err != nil {
+			return err
+		}
+		*p = v
+	case *float64:
+		v, err := strconv.ParseFloat(value, 64)
+		if err != nil {
+			return err
+		}
+		*p = v
+	case *bool:
+		v, err := strconv.ParseBool(value)
+		if err != nil {
+			return err
+		}
+		*p = v
+	case *time.Duration:
+		v, err := time.ParseDuration(value)
+		if err != nil {
+			return err
+		}
+		*p = v
+	default:
+		return fmt.Errorf("unsupported setting type %T", field)
+	}
+	return nil
+}
@@ -3,7 +3,9 @@ package handler
 import (
 	"context"
 
+	"github.com/paudelanil/grpc-crud/internal/middleware"
 	"github.com/paudelanil/grpc-crud/internal/service"
+	"github.com/paudelanil/grpc-crud/models"
 	"github.com/paudelanil/grpc-crud/pb"
 	"google.golang.org/grpc/codes"
 	"google.golang.org/grpc/status"
@@ -34,7 +36,7 @@ func (h *AccountHandler) CreateUser(ctx context.Context, req *pb.CreateCustomerR
 
 	response, err := h.customerService.CreateCustomer(ctx, req)
 	if err != nil {
-		return nil, status.Error(codes.Internal, err.Error())
+		return nil, toStatus(ctx, err)
 	}
 
 	return response, nil
@@ -46,9 +48,21 @@ func (h *AccountHandler) GetUser(ctx context.Context, req *pb.GetCustomerRequest
 		return nil, status.Error(codes.InvalidArgument, "request cannot be nil")
 	}
 
+	// End users may only read their own customer profile
+	customerID, restricted, err := selfServiceCustomer(ctx)
+	if err != nil {
+		return nil, err
+	}
+	if restricted {
+		if req.CustomerId != "" && req.CustomerId != customerID {
+			return nil, status.Error(codes.PermissionDenied, "cannot access another customer's profile")
+		}
+		req = &pb.GetCustomerRequest{CustomerId: customerID}
+	}
+
 	response, err := h.customerService.GetCustomer(ctx, req)
 	if err != nil {
-		return nil, status.Error(codes.NotFound, err.Error())
+		return nil, toStatus(ctx, err)
 	}
 
 	return response, nil
@@ -62,7 +76,7 @@ func (h *AccountHandler) UpdateUser(ctx context.Context, req *pb.UpdateCustomerR
 
 	response, err := h.customerService.UpdateCustomer(ctx, req)
 	if err != nil {
-		return nil, status.Error(codes.Internal, err.Error())
+		return nil, toStatus(ctx, err)
 	}
 
 	return response, nil
@@ -76,7 +90,7 @@ func (h *AccountHandler) DeleteUser(ctx context.Context, req *pb.DeleteCustomerR
 
 	response, err := h.customerService.DeleteCustomer(ctx, req)
 	if err != nil {
-		return nil, status.Error(codes.Internal, err.Error())
+		return nil, toStatus(ctx, err)
 	}
 
 	return response, nil
@@ -90,7 +104,7 @@ func (h *AccountHandler) ListUsers(ctx context.Context, req *pb.ListCustomerRequ
 
 	response, err := h.customerService.ListCustomers(ctx, req)
 	if err != nil {
-		return nil, status.Error(codes.Internal, err.Error())
+		return nil, toStatus(ctx, err)
 	}
 
 	return response, nil
@@ -106,21 +120,31 @@ func (h *AccountHandler) CreateAccount(ctx context.Context, req *pb.CreateAccoun
 
 	response, err := h.accountService.CreateAccount(ctx, req)
 	if err != nil {
-		return nil, status.Error(codes.Internal, err.Error())
+		return nil, toStatus(ctx, err)
 	}
 
 	return response, nil
 }
 
-// GetAccount retrieves an account by ID
+// GetAccount retrieves an account by ID or account number
 func (h *AccountHandler) GetAccount(ctx context.Context, req *pb.GetAccountRequest) (*pb.GetAccountResponse, error) {
 	if req == nil {
 		return nil, status.Error(codes.InvalidArgument, "request cannot be nil")
 	}
 
+	customerID, restricted, err := selfServiceCustomer(ctx)
+	if err != nil {
+		return nil, err
+	}
+
 	response, err := h.accountService.GetAccount(ctx, req)
 	if err != nil {
-		return nil, status.Error(codes.NotFound, err.Error())
+		return nil, toStatus(ctx, err)
+	}
+
+	// Other customers' accounts look the same as missing ones to end users
+	if restricted && response.CustomerId != customerID {
+		return nil, status.Error(codes.NotFound, "account not found")
 	}
 
 	return response, nil
@@ -132,9 +156,14 @@ func (h *AccountHandler) UpdateAccount(ctx context.Context, req *pb.UpdateAccoun
 		return nil, status.Error(codes.InvalidArgument, "request cannot be nil")
 	}
 
-	response, err := h.accountService.UpdateAccount(ctx, req)
+	user, err := middleware.GetUserFromContext(ctx)
 	if err != nil {
-		return nil, status.Error(codes.Internal, err.Error())
+		return nil, err
+	}
+
+	response, err := h.accountService.UpdateAccount(ctx, user.UserID, req)
+	if err != nil {
+		return nil, toStatus(ctx, err)
 	}
 
 	return response, nil
@@ -148,7 +177,7 @@ func (h *AccountHandler) DeleteAccount(ctx context.Context, req *pb.DeleteAccoun
 
 	response, err := h.accountService.DeleteAccount(ctx, req)
 	if err != nil {
-		return nil, status.Error(codes.Internal, err.Error())
+		return nil, toStatus(ctx, err)
 	}
 
 	return response, nil
@@ -160,10 +189,158 @@ func (h *AccountHandler) ListAccounts(ctx context.Context, req *pb.ListAccountRe
 		return nil, status.Error(codes.InvalidArgument, "request cannot be nil")
 	}
 
-	response, err := h.accountService.ListAccounts(ctx, req)
+	customerID, restricted, err := selfServiceCustomer(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	// End users only ever see their own accounts
+	var response *pb.ListAccountResponse
+	if restricted {
+		response, err = h.accountService.ListCustomerAccounts(ctx, customerID, req)
+	} else {
+		response, err = h.accountService.ListAccounts(ctx, req)
+	}
+	if err != nil {
+		return nil, toStatus(ctx, err)
+	}
+
+	return response, nil
+}
+
+// Money movement operations
+
+// Deposit credits money to an account
+func (h *AccountHandler) Deposit(ctx context.Context, req *pb.DepositRequest) (*pb.DepositResponse, error) {
+	if req == nil {
+		return nil, status.Error(codes.InvalidArgument, "request cannot be nil")
+	}
+
+	response, err := h.accountService.Deposit(ctx, req)
+	if err != nil {
+		return nil, toStatus(ctx, err)
+	}
+
+	return response, nil
+}
+
+// Withdraw debits money from an account
+func (h *AccountHandler) Withdraw(ctx context.Context, req *pb.WithdrawRequest) (*pb.WithdrawResponse, error) {
+	if req == nil {
+		return nil, status.Error(codes.InvalidArgument, "request cannot be nil")
+	}
+
+	response, err := h.accountService.Withdraw(ctx, req)
+	if err != nil {
+		return nil, toStatus(ctx, err)
+	}
+
+	return response, nil
+}
+
+// Transfer moves money between two accounts
+func (h *AccountHandler) Transfer(ctx context.Context, req *pb.TransferRequest) (*pb.TransferResponse, error) {
+	if req == nil {
+		return nil, status.Error(codes.InvalidArgument, "request cannot be nil")
+	}
+
+	response, err := h.accountService.Transfer(ctx, req)
+	if err != nil {
+		return nil, toStatus(ctx, err)
+	}
+
+	return response, nil
+}
+
+// Account lifecycle operations
+
+// FreezeAccount blocks money leaving an account
+func (h *AccountHandler) FreezeAccount(ctx context.Context, req *pb.FreezeAccountRequest) (*pb.FreezeAccountResponse, error) {
+	if req == nil {
+		return nil, status.Error(codes.InvalidArgument, "request cannot be nil")
+	}
+
+	user, err := middleware.GetUserFromContext(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	response, err := h.accountService.FreezeAccount(ctx, user.UserID, req)
 	if err != nil {
-		return nil, status.Error(codes.Internal, err.Error())
+		return nil, toStatus(ctx, err)
 	}
 
 	return response, nil
 }
+
+// UnfreezeAccount returns a frozen account to active
+func (h *AccountHandler) UnfreezeAccount(ctx context.Context, req *pb.UnfreezeAccountRequest) (*pb.UnfreezeAccountResponse, error) {
+	if req == nil {
+		return nil, status.Error(codes.InvalidArgument, "request cannot be nil")
+	}
+
+	user, err := middleware.GetUserFromContext(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	response, err := h.accountService.UnfreezeAccount(ctx, user.UserID, req)
+	if err != nil {
+		return nil, toStatus(ctx, err)
+	}
+
+	return response, nil
+}
+
+// CloseAccount closes an account with a zero balance
+func (h *AccountHandler) CloseAccount(ctx context.Context, req *pb.CloseAccountRequest) (*pb.CloseAccountResponse, error) {
+	if req == nil {
+		return nil, status.Error(codes.InvalidArgument, "request cannot be nil")
+	}
+
+	user, err := middleware.GetUserFromContext(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	response, err := h.accountService.CloseAccount(ctx, user.UserID, req)
+	if err != nil {
+		return nil, toStatus(ctx, err)
+	}
+
+	return response, nil
+}
+
+// GetAccountStatusHistory lists the status changes of an account
+func (h *AccountHandler) GetAccountStatusHistory(ctx context.Context, req *pb.GetAccountStatusHistoryRequest) (*pb.GetAccountStatusHistoryResponse, error) {
+	if req == nil {
+		return nil, status.Error(codes.InvalidArgument, "request cannot be nil")
+	}
+
+	response, err := h.accountService.GetAccountStatusHistory(ctx, req)
+	if err != nil {
+		return nil, toStatus(ctx, err)
+	}
+
+	return response, nil
+}
+
+// selfServiceCustomer returns the caller's customer ID when the caller is an
+// end user who may only access their own data. End users that are not linked
+// to a customer are denied.
+func selfServiceCustomer(ctx context.Context) (string, bool, error) {
+	user, err := middleware.GetUserFromContext(ctx)
+	if err != nil {
+		return "", false, err
+	}
+
+	if user.Role != models.RoleCustomer {
+		return "", false, nil
+	}
+
+	if user.CustomerID == "" {
+		return "", true, status.Error(codes.PermissionDenied, "user is not linked to a customer")
+	}
+
+	return user.CustomerID, true, nil
+}
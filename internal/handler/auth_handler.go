@@ -3,6 +3,7 @@ package handler
 import (
 	"context"
 
+	"github.com/paudelanil/grpc-crud/internal/middleware"
 	"github.com/paudelanil/grpc-crud/internal/service"
 	"github.com/paudelanil/grpc-crud/pb"
 	"google.golang.org/grpc/codes"
@@ -24,23 +25,14 @@ func NewAuthHandler(authService service.IAuthService) *AuthHandler {
 
 // Login handles user login requests
 func (h *AuthHandler) Login(ctx context.Context, req *pb.UserLoginRequest) (*pb.UserLoginResponse, error) {
-	// Validate input
 	if req == nil {
 		return nil, status.Error(codes.InvalidArgument, "request cannot be nil")
 	}
 
-	if req.Username == "" {
-		return nil, status.Error(codes.InvalidArgument, "username is required")
-	}
-
-	if req.Password == "" {
-		return nil, status.Error(codes.InvalidArgument, "password is required")
-	}
-
 	// Call service layer
 	response, err := h.authService.Login(ctx, req)
 	if err != nil {
-		return nil, status.Error(codes.Unauthenticated, err.Error())
+		return nil, toStatus(ctx, err)
 	}
 
 	return response, nil
@@ -52,22 +44,10 @@ func (h *AuthHandler) Register(ctx context.Context, req *pb.UserRegisterRequest)
 		return nil, status.Error(codes.InvalidArgument, "request cannot be nil")
 	}
 
-	if req.Username == "" {
-		return nil, status.Error(codes.InvalidArgument, "username is required")
-	}
-
-	if req.Password == "" {
-		return nil, status.Error(codes.InvalidArgument, "password is required")
-	}
-
-	if req.Email == "" {
-		return nil, status.Error(codes.InvalidArgument, "email is required")
-	}
-
 	// Call service layer
 	err := h.authService.Register(ctx, req.Username, req.Email, req.Password)
 	if err != nil {
-		return nil, status.Error(codes.Internal, err.Error())
+		return nil, toStatus(ctx, err)
 	}
 	return &pb.UserRegisterResponse{Message: "User Registered Successfully"}, nil
 }
@@ -78,14 +58,15 @@ func (h *AuthHandler) Logout(ctx context.Context, req *pb.UserLogoutRequest) (*p
 		return nil, status.Error(codes.InvalidArgument, "request cannot be nil")
 	}
 
-	if req.AccessToken == "" {
-		return nil, status.Error(codes.InvalidArgument, "access token is required")
+	user, err := middleware.GetUserFromContext(ctx)
+	if err != nil {
+		return nil, err
 	}
 
 	// Call service layer
-	response, err := h.authService.Logout(ctx, req)
+	response, err := h.authService.Logout(ctx, user.UserID, req)
 	if err != nil {
-		return nil, status.Error(codes.Internal, err.Error())
+		return nil, toStatus(ctx, err)
 	}
 
 	return response, nil
@@ -97,15 +78,39 @@ func (h *AuthHandler) RefreshToken(ctx context.Context, req *pb.TokenRequest) (*
 		return nil, status.Error(codes.InvalidArgument, "request cannot be nil")
 	}
 
-	if req.RefreshToken == "" {
-		return nil, status.Error(codes.InvalidArgument, "refresh token is required")
-	}
-
 	// Call service layer
 	response, err := h.authService.RefreshToken(ctx, req)
 	if err != nil {
-		return nil, status.Error(codes.Unauthenticated, err.Error())
+		return nil, toStatus(ctx, err)
 	}
 
 	return response, nil
 }
+
+// UpdateUserRole handles role change requests
+func (h *AuthHandler) UpdateUserRole(ctx context.Context, req *pb.UpdateUserRoleRequest) (*pb.UpdateUserRoleResponse, error) {
+	if req == nil {
+		return nil, status.Error(codes.InvalidArgument, "request cannot be nil")
+	}
+
+	// Call service layer
+	if err := h.authService.UpdateUserRole(ctx, req.UserId, req.Role); err != nil {
+		return nil, toStatus(ctx, err)
+	}
+
+	return &pb.UpdateUserRoleResponse{Message: "User role updated successfully"}, nil
+}
+
+// LinkCustomer handles requests to link a user to a customer
+func (h *AuthHandler) LinkCustomer(ctx context.Context, req *pb.LinkCustomerRequest) (*pb.LinkCustomerResponse, error) {
+	if req == nil {
+		return nil, status.Error(codes.InvalidArgument, "request cannot be nil")
+	}
+
+	// Call service layer
+	if err := h.authService.LinkCustomer(ctx, req.UserId, req.CustomerId); err != nil {
+		return nil, toStatus(ctx, err)
+	}
+
+	return &pb.LinkCustomerResponse{Message: "User linked to customer successfully"}, nil
+}
@@ -0,0 +1,111 @@
+package handler
+
+import (
+	"context"
+	"errors"
+
+	"github.com/paudelanil/grpc-crud/internal/logging"
+	"github.com/paudelanil/grpc-crud/internal/repository"
+	"github.com/paudelanil/grpc-crud/internal/service"
+	"google.golang.org/genproto/googleapis/rpc/errdetails"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/protoadapt"
+)
+
+// errorDomain is the ErrorInfo domain of errors raised by this server
+const errorDomain = "grpc-crud"
+
+// toStatus maps a service error to a gRPC status with machine-readable
+// details. The status message is the domain error's own, without the
+// context services wrap around it. Errors that are already a gRPC status
+// pass through unchanged.
+// Unexpected errors are logged and returned as an opaque Internal error so
+// database details never reach the caller.
+func toStatus(ctx context.Context, err error) error {
+	if err == nil {
+		return nil
+	}
+
+	if _, ok := status.FromError(err); ok {
+		return err
+	}
+
+	var (
+		validationErr *service.ValidationError
+		notFoundErr   *repository.NotFoundError
+		existsErr     *repository.AlreadyExistsError
+		conflictErr   *repository.ConflictError
+		authErr       *service.AuthError
+		permissionErr *service.PermissionError
+	)
+
+	switch {
+	case errors.As(err, &validationErr):
+		badRequest := &errdetails.BadRequest{}
+		for _, v := range validationErr.Violations {
+			badRequest.FieldViolations = append(badRequest.FieldViolations, &errdetails.BadRequest_FieldViolation{
+				Field:       v.Field,
+				Description: v.Description,
+			})
+		}
+		return withDetails(codes.InvalidArgument, validationErr.Error(), errorInfo("INVALID_ARGUMENT", nil), badRequest)
+
+	case errors.As(err, &notFoundErr):
+		return withDetails(codes.NotFound, notFoundErr.Error(), errorInfo("NOT_FOUND", map[string]string{
+			"resource": notFoundErr.Resource,
+		}))
+
+	case errors.As(err, &existsErr):
+		if existsErr.Field == "" {
+			return withDetails(codes.AlreadyExists, existsErr.Error(), errorInfo("ALREADY_EXISTS", map[string]string{
+				"resource": existsErr.Resource,
+			}))
+		}
+		return withDetails(codes.AlreadyExists, existsErr.Error(), errorInfo("ALREADY_EXISTS", map[string]string{
+			"resource": existsErr.Resource,
+			"field":    existsErr.Field,
+		}), &errdetails.BadRequest{
+			FieldViolations: []*errdetails.BadRequest_FieldViolation{
+				{Field: existsErr.Field, Description: existsErr.Error()},
+			},
+		})
+
+	case errors.As(err, &conflictErr):
+		return withDetails(codes.FailedPrecondition, conflictErr.Error(), errorInfo(conflictErr.Reason, nil))
+
+	case errors.As(err, &authErr):
+		return withDetails(codes.Unauthenticated, authErr.Error(), errorInfo(authErr.Reason, nil))
+
+	case errors.As(err, &permissionErr):
+		return withDetails(codes.PermissionDenied, permissionErr.Error(), errorInfo(permissionErr.Reason, nil))
+
+	case errors.Is(err, context.Canceled):
+		return status.Error(codes.Canceled, "request canceled")
+
+	case errors.Is(err, context.DeadlineExceeded):
+		return status.Error(codes.DeadlineExceeded, "request deadline exceeded")
+	}
+
+	logging.FromContext(ctx).ErrorContext(ctx, "request failed", "error", err)
+	return status.Error(codes.Internal, "internal error")
+}
+
+// errorInfo builds the ErrorInfo detail for a reason
+func errorInfo(reason string, metadata map[string]string) *errdetails.ErrorInfo {
+	return &errdetails.ErrorInfo{
+		Reason:   reason,
+		Domain:   errorDomain,
+		Metadata: metadata,
+	}
+}
+
+// withDetails returns a status error carrying the details. If they cannot
+// be attached the plain status is returned.
+func withDetails(code codes.Code, msg string, details ...protoadapt.MessageV1) error {
+	st, err := status.New(code, msg).WithDetails(details...)
+	if err != nil {
+		return status.Error(code, msg)
+	}
+	return st.Err()
+}
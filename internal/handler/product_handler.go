@@ -0,0 +1,79 @@
+package handler
+
+import (
+	"context"
+
+	"github.com/paudelanil/grpc-crud/internal/service"
+	"github.com/paudelanil/grpc-crud/pb"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// ProductHandler handles account product catalog gRPC requests
+type ProductHandler struct {
+	pb.UnimplementedProductServiceServer
+	productService service.IProductService
+}
+
+// NewProductHandler creates a new instance of ProductHandler
+func NewProductHandler(productService service.IProductService) *ProductHandler {
+	return &ProductHandler{
+		productService: productService,
+	}
+}
+
+// CreateProduct adds a product to the catalog
+func (h *ProductHandler) CreateProduct(ctx context.Context, req *pb.CreateProductRequest) (*pb.CreateProductResponse, error) {
+	if req == nil {
+		return nil, status.Error(codes.InvalidArgument, "request cannot be nil")
+	}
+
+	response, err := h.productService.CreateProduct(ctx, req)
+	if err != nil {
+		return nil, toStatus(ctx, err)
+	}
+
+	return response, nil
+}
+
+// GetProduct retrieves a product by code
+func (h *ProductHandler) GetProduct(ctx context.Context, req *pb.GetProductRequest) (*pb.AccountProduct, error) {
+	if req == nil {
+		return nil, status.Error(codes.InvalidArgument, "request cannot be nil")
+	}
+
+	response, err := h.productService.GetProduct(ctx, req)
+	if err != nil {
+		return nil, toStatus(ctx, err)
+	}
+
+	return response, nil
+}
+
+// UpdateProduct replaces the settings of a product
+func (h *ProductHandler) UpdateProduct(ctx context.Context, req *pb.UpdateProductRequest) (*pb.UpdateProductResponse, error) {
+	if req == nil {
+		return nil, status.Error(codes.InvalidArgument, "request cannot be nil")
+	}
+
+	response, err := h.productService.UpdateProduct(ctx, req)
+	if err != nil {
+		return nil, toStatus(ctx, err)
+	}
+
+	return response, nil
+}
+
+// ListProducts lists the catalog
+func (h *ProductHandler) ListProducts(ctx context.Context, req *pb.ListProductsRequest) (*pb.ListProductsResponse, error) {
+	if req == nil {
+		return nil, status.Error(codes.InvalidArgument, "request cannot be nil")
+	}
+
+	response, err := h.productService.ListProducts(ctx, req)
+	if err != nil {
+		return nil, toStatus(ctx, err)
+	}
+
+	return response, nil
+}
@@ -0,0 +1,107 @@
+package handler
+
+import (
+	"context"
+
+	"github.com/paudelanil/grpc-crud/internal/service"
+	"github.com/paudelanil/grpc-crud/pb"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+)
+
+// exportChunkSize is the most file data sent in one message
+const exportChunkSize = 64 * 1024
+
+// StatementHandler handles account statement gRPC requests
+type StatementHandler struct {
+	pb.UnimplementedAccountStatementServiceServer
+	statementService service.IStatementService
+}
+
+// NewStatementHandler creates a new instance of StatementHandler
+func NewStatementHandler(statementService service.IStatementService) *StatementHandler {
+	return &StatementHandler{
+		statementService: statementService,
+	}
+}
+
+// GetStatement returns one page of an account statement
+func (h *StatementHandler) GetStatement(ctx context.Context, req *pb.GetStatementRequest) (*pb.GetStatementResponse, error) {
+	if req == nil {
+		return nil, status.Error(codes.InvalidArgument, "request cannot be nil")
+	}
+
+	// End users only ever see statements of their own accounts
+	customerID, _, err := selfServiceCustomer(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	response, err := h.statementService.GetStatement(ctx, customerID, req)
+	if err != nil {
+		return nil, toStatus(ctx, err)
+	}
+
+	return response, nil
+}
+
+// StreamStatement streams a whole account statement
+func (h *StatementHandler) StreamStatement(req *pb.StreamStatementRequest, stream pb.AccountStatementService_StreamStatementServer) error {
+	if req == nil {
+		return status.Error(codes.InvalidArgument, "request cannot be nil")
+	}
+
+	ctx := stream.Context()
+	customerID, _, err := selfServiceCustomer(ctx)
+	if err != nil {
+		return err
+	}
+
+	if err := h.statementService.StreamStatement(ctx, customerID, req, stream.Send); err != nil {
+		return toStatus(ctx, err)
+	}
+
+	return nil
+}
+
+// ExportStatement streams an account statement rendered as a file. The
+// content type and file name are also sent as the x-content-type and
+// x-filename response headers.
+func (h *StatementHandler) ExportStatement(req *pb.ExportStatementRequest, stream pb.AccountStatementService_ExportStatementServer) error {
+	if req == nil {
+		return status.Error(codes.InvalidArgument, "request cannot be nil")
+	}
+
+	ctx := stream.Context()
+	customerID, _, err := selfServiceCustomer(ctx)
+	if err != nil {
+		return err
+	}
+
+	file, err := h.statementService.ExportStatement(ctx, customerID, req)
+	if err != nil {
+		return toStatus(ctx, err)
+	}
+
+	header := metadata.Pairs("x-content-type", file.ContentType, "x-filename", file.Filename)
+	if err := grpc.SetHeader(ctx, header); err != nil {
+		return err
+	}
+
+	// The first chunk names the file, even when the file is empty
+	data := file.Data
+	chunk := &pb.StatementFileChunk{ContentType: file.ContentType, Filename: file.Filename}
+	for {
+		n := min(len(data), exportChunkSize)
+		chunk.Data, data = data[:n], data[n:]
+		if err := stream.Send(chunk); err != nil {
+			return err
+		}
+		if len(data) == 0 {
+			return nil
+		}
+		chunk = &pb.StatementFileChunk{}
+	}
+}
@@ -0,0 +1,113 @@
+package healthcheck
+
+import (
+	"context"
+	"log/slog"
+	"sync"
+	"time"
+
+	"google.golang.org/grpc/health"
+	healthpb "google.golang.org/grpc/health/grpc_health_v1"
+)
+
+// Pinger is implemented by *sql.DB
+type Pinger interface {
+	PingContext(ctx context.Context) error
+}
+
+// Checker keeps the gRPC health status of the served services in line with
+// readiness and database connectivity. A service is SERVING only while the
+// server is ready and the last database ping succeeded.
+type Checker struct {
+	server   *health.Server
+	db       Pinger
+	services []string
+	interval time.Duration
+	timeout  time.Duration
+
+	mu    sync.Mutex
+	ready bool
+	dbUp  bool
+}
+
+// NewChecker creates a Checker for the given services. Every service starts
+// as NOT_SERVING until SetReady(true) is called.
+func NewChecker(server *health.Server, db Pinger, services []string, interval, timeout time.Duration) *Checker {
+	c := &Checker{
+		server:   server,
+		db:       db,
+		services: services,
+		interval: interval,
+		timeout:  timeout,
+	}
+	c.publish()
+	return c
+}
+
+// SetReady marks the server as ready (after startup migrations) or not
+// ready (while shutting down)
+func (c *Checker) SetReady(ready bool) {
+	c.mu.Lock()
+	c.ready = ready
+	c.mu.Unlock()
+
+	if ready {
+		c.check(context.Background())
+		return
+	}
+	c.publish()
+}
+
+// Run pings the database at every interval until ctx is cancelled
+func (c *Checker) Run(ctx context.Context) error {
+	ticker := time.NewTicker(c.interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return nil
+		case <-ticker.C:
+			c.check(ctx)
+		}
+	}
+}
+
+// check pings the database and publishes the resulting status
+func (c *Checker) check(ctx context.Context) {
+	pingCtx, cancel := context.WithTimeout(ctx, c.timeout)
+	err := c.db.PingContext(pingCtx)
+	cancel()
+
+	c.mu.Lock()
+	wasUp := c.dbUp
+	c.dbUp = err == nil
+	c.mu.Unlock()
+
+	switch {
+	case err != nil && wasUp:
+		slog.Warn("database is unreachable, reporting NOT_SERVING", "error", err)
+	case err == nil && !wasUp:
+		slog.Info("database is reachable")
+	}
+
+	c.publish()
+}
+
+// publish sets the status of the overall server and of every service
+func (c *Checker) publish() {
+	c.mu.Lock()
+	serving := c.ready && c.dbUp
+	c.mu.Unlock()
+
+	status := healthpb.HealthCheckResponse_NOT_SERVING
+	if serving {
+		status = healthpb.HealthCheckResponse_SERVING
+	}
+
+	// The empty service name reports the server as a whole
+	c.server.SetServingStatus("", status)
+	for _, service := range c.services {
+		c.server.SetServingStatus(service, status)
+	}
+}
@@ -0,0 +1,178 @@
+package lifecycle
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"os"
+	"os/signal"
+	"sync"
+	"syscall"
+	"time"
+)
+
+// hook is a named shutdown step
+type hook struct {
+	name string
+	stop func(ctx context.Context) error
+}
+
+// Manager owns the process lifecycle: it runs background workers, waits for
+// a termination signal or a fatal error, then stops workers and runs the
+// registered shutdown hooks within a deadline
+type Manager struct {
+	timeout time.Duration
+
+	ctx    context.Context
+	cancel context.CancelFunc
+
+	mu      sync.Mutex
+	hooks   []hook
+	workers sync.WaitGroup
+
+	fatal     chan error
+	fatalOnce sync.Once
+}
+
+// New creates a Manager whose shutdown must complete within timeout
+func New(timeout time.Duration) *Manager {
+	ctx, cancel := context.WithCancel(context.Background())
+	return &Manager{
+		timeout: timeout,
+		ctx:     ctx,
+		cancel:  cancel,
+		fatal:   make(chan error, 1),
+	}
+}
+
+// Context is cancelled when shutdown begins
+func (m *Manager) Context() context.Context {
+	return m.ctx
+}
+
+// OnShutdown registers a shutdown hook. Hooks run in reverse order of
+// registration, so resources registered first are released last.
+func (m *Manager) OnShutdown(name string, stop func(ctx context.Context) error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.hooks = append(m.hooks, hook{name: name, stop: stop})
+}
+
+// Go runs a background worker until shutdown cancels its context. A worker
+// that returns an error other than context.Canceled shuts the process down.
+func (m *Manager) Go(name string, run func(ctx context.Context) error) {
+	m.workers.Add(1)
+	go func() {
+		defer m.workers.Done()
+		if err := run(m.ctx); err != nil && !errors.Is(err, context.Canceled) {
+			m.Fail(fmt.Errorf("%s: %w", name, err))
+		}
+	}()
+}
+
+// Fail starts a shutdown because of a fatal error; only the first error is kept
+func (m *Manager) Fail(err error) {
+	m.fatalOnce.Do(func() {
+		m.fatal <- err
+	})
+}
+
+// Run blocks until SIGINT, SIGTERM or a fatal error, then shuts down. It
+// returns the fatal error, if any, joined with any shutdown errors.
+func (m *Manager) Run() error {
+	signals := make(chan os.Signal, 1)
+	signal.Notify(signals, syscall.SIGINT, syscall.SIGTERM)
+	defer signal.Stop(signals)
+
+	var cause error
+	select {
+	case sig := <-signals:
+		slog.Info("shutting down", "signal", sig.String())
+	case cause = <-m.fatal:
+		slog.Error("shutting down after fatal error", "error", cause)
+	}
+
+	return errors.Join(cause, m.Shutdown())
+}
+
+// Shutdown cancels the workers, waits for them and runs the shutdown hooks.
+// Everything shares one deadline; hooks still run after it has passed so
+// they can fall back to a forced stop.
+func (m *Manager) Shutdown() error {
+	ctx, cancel := context.WithTimeout(context.Background(), m.timeout)
+	defer cancel()
+
+	m.cancel()
+
+	done := make(chan struct{})
+	go func() {
+		m.workers.Wait()
+		close(done)
+	}()
+	select {
+	case <-done:
+	case <-ctx.Done():
+		slog.Warn("background workers did not stop in time", "timeout", m.timeout.String())
+	}
+
+	m.mu.Lock()
+	hooks := m.hooks
+	m.hooks = nil
+	m.mu.Unlock()
+
+	var errs []error
+	for i := len(hooks) - 1; i >= 0; i-- {
+		h := hooks[i]
+		slog.Info("stopping", "component", h.name)
+		if err := h.stop(ctx); err != nil {
+			errs = append(errs, fmt.Errorf("%s: %w", h.name, err))
+		}
+	}
+
+	return errors.Join(errs...)
+}
+
+// Every calls fn at each interval until ctx is cancelled. It is meant to be
+// passed to Go for periodic maintenance jobs.
+func Every(interval time.Duration, fn func(ctx context.Context)) func(ctx context.Context) error {
+	return func(ctx context.Context) error {
+		ticker := time.NewTicker(interval)
+		defer ticker.Stop()
+		for {
+			select {
+			case <-ctx.Done():
+				return nil
+			case <-ticker.C:
+				fn(ctx)
+			}
+		}
+	}
+}
+
+// GracefulStopper is implemented by *grpc.Server
+type GracefulStopper interface {
+	GracefulStop()
+	Stop()
+}
+
+// StopGracefully returns a shutdown hook that lets in-flight RPCs finish and
+// forces the server to stop if they are still running at the deadline
+func StopGracefully(server GracefulStopper) func(ctx context.Context) error {
+	return func(ctx context.Context) error {
+		done := make(chan struct{})
+		go func() {
+			server.GracefulStop()
+			close(done)
+		}()
+
+		select {
+		case <-done:
+			return nil
+		case <-ctx.Done():
+			server.Stop()
+			<-done
+			return errors.New("graceful stop timed out, in-flight RPCs were cancelled")
+		}
+	}
+}
@@ -0,0 +1,153 @@
+package logging
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"log/slog"
+	"strings"
+
+	"github.com/google/uuid"
+	"google.golang.org/protobuf/encoding/protojson"
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/reflect/protoreflect"
+)
+
+// Output formats
+const (
+	FormatJSON = "json"
+	FormatText = "text"
+)
+
+// redacted replaces the value of sensitive attributes and fields
+const redacted = "[REDACTED]"
+
+// sensitiveKeys are attribute keys and proto field names whose values are
+// never written to the log
+var sensitiveKeys = map[string]bool{
+	"password":      true,
+	"token":         true,
+	"access_token":  true,
+	"refresh_token": true,
+	"authorization": true,
+	"jwt_secret":    true,
+}
+
+// New creates a logger writing to w in the given format at or above level
+// (debug, info, warn or error)
+func New(w io.Writer, level, format string) (*slog.Logger, error) {
+	var lvl slog.Level
+	if err := lvl.UnmarshalText([]byte(level)); err != nil {
+		return nil, fmt.Errorf("invalid log level %q", level)
+	}
+
+	opts := &slog.HandlerOptions{Level: lvl, ReplaceAttr: redactAttr}
+	switch format {
+	case FormatJSON:
+		return slog.New(slog.NewJSONHandler(w, opts)), nil
+	case FormatText:
+		return slog.New(slog.NewTextHandler(w, opts)), nil
+	default:
+		return nil, fmt.Errorf("invalid log format %q", format)
+	}
+}
+
+// redactAttr hides the value of attributes with a sensitive key
+func redactAttr(groups []string, a slog.Attr) slog.Attr {
+	if sensitiveKeys[strings.ToLower(a.Key)] {
+		return slog.String(a.Key, redacted)
+	}
+	return a
+}
+
+type loggerKey struct{}
+type requestIDKey struct{}
+
+// WithLogger returns a context carrying the logger
+func WithLogger(ctx context.Context, logger *slog.Logger) context.Context {
+	return context.WithValue(ctx, loggerKey{}, logger)
+}
+
+// FromContext returns the logger carried by the context, or the default
+// logger when there is none. Request scoped loggers already carry the
+// request ID and method.
+func FromContext(ctx context.Context) *slog.Logger {
+	if logger, ok := ctx.Value(loggerKey{}).(*slog.Logger); ok {
+		return logger
+	}
+	return slog.Default()
+}
+
+// With adds attributes to the context logger
+func With(ctx context.Context, args ...any) context.Context {
+	return WithLogger(ctx, FromContext(ctx).With(args...))
+}
+
+// WithRequestID returns a context carrying the request ID
+func WithRequestID(ctx context.Context, requestID string) context.Context {
+	return context.WithValue(ctx, requestIDKey{}, requestID)
+}
+
+// RequestID returns the request ID carried by the context, if any
+func RequestID(ctx context.Context) string {
+	requestID, _ := ctx.Value(requestIDKey{}).(string)
+	return requestID
+}
+
+// NewRequestID generates a request ID
+func NewRequestID() string {
+	return uuid.New().String()
+}
+
+// Proto returns a log value rendering msg as JSON with sensitive fields
+// redacted. The message is only rendered if the record is written.
+func Proto(msg proto.Message) slog.LogValuer {
+	return protoValue{msg: msg}
+}
+
+type protoValue struct {
+	msg proto.Message
+}
+
+func (v protoValue) LogValue() slog.Value {
+	if v.msg == nil {
+		return slog.StringValue("null")
+	}
+	clone := proto.Clone(v.msg)
+	redactMessage(clone.ProtoReflect())
+	out, err := protojson.Marshal(clone)
+	if err != nil {
+		return slog.StringValue("<unprintable " + string(v.msg.ProtoReflect().Descriptor().FullName()) + ">")
+	}
+	return slog.AnyValue(json.RawMessage(out))
+}
+
+// redactMessage overwrites sensitive string fields, descending into
+// nested messages
+func redactMessage(m protoreflect.Message) {
+	m.Range(func(fd protoreflect.FieldDescriptor, v protoreflect.Value) bool {
+		switch {
+		case sensitiveKeys[string(fd.Name())] && fd.Kind() == protoreflect.StringKind && !fd.IsList() && !fd.IsMap():
+			m.Set(fd, protoreflect.ValueOfString(redacted))
+		case fd.Kind() == protoreflect.MessageKind || fd.Kind() == protoreflect.GroupKind:
+			switch {
+			case fd.IsList():
+				list := v.List()
+				for i := 0; i < list.Len(); i++ {
+					redactMessage(list.Get(i).Message())
+				}
+			case fd.IsMap():
+				if fd.MapValue().Kind() == protoreflect.MessageKind {
+					v.Map().Range(func(_ protoreflect.MapKey, mv protoreflect.Value) bool {
+						redactMessage(mv.Message())
+						return true
+					})
+				}
+			default:
+				redactMessage(v.Message())
+			}
+		}
+		return true
+	})
+}
@@ -0,0 +1,130 @@
+package metrics
+
+import (
+	"database/sql"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/collectors"
+	"github.com/prometheus/client_golang/prometheus/promhttp"
+	"google.golang.org/grpc/codes"
+)
+
+// Metrics holds the Prometheus collectors of the server
+type Metrics struct {
+	registry *prometheus.Registry
+
+	started  *prometheus.CounterVec
+	handled  *prometheus.CounterVec
+	duration *prometheus.HistogramVec
+	panics   *prometheus.CounterVec
+
+	customersCreated prometheus.Counter
+	accountsOpened   prometheus.Counter
+	loginsFailed     *prometheus.CounterVec
+}
+
+// New creates the collectors and registers them with a fresh registry
+func New() *Metrics {
+	m := &Metrics{
+		registry: prometheus.NewRegistry(),
+		started: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Name: "grpc_server_started_total",
+			Help: "Total number of RPCs started on the server.",
+		}, []string{"grpc_service", "grpc_method"}),
+		handled: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Name: "grpc_server_handled_total",
+			Help: "Total number of RPCs completed on the server, by status code.",
+		}, []string{"grpc_service", "grpc_method", "grpc_code"}),
+		duration: prometheus.NewHistogramVec(prometheus.HistogramOpts{
+			Name:    "grpc_server_handling_seconds",
+			Help:    "Latency of RPCs handled by the server.",
+			Buckets: []float64{.005, .01, .025, .05, .1, .25, .5, 1, 2.5, 5, 10},
+		}, []string{"grpc_service", "grpc_method"}),
+		panics: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Name: "grpc_server_panics_recovered_total",
+			Help: "Total number of panics recovered while handling RPCs.",
+		}, []string{"grpc_service", "grpc_method"}),
+		customersCreated: prometheus.NewCounter(prometheus.CounterOpts{
+			Name: "grpc_crud_customers_created_total",
+			Help: "Total number of customers created.",
+		}),
+		accountsOpened: prometheus.NewCounter(prometheus.CounterOpts{
+			Name: "grpc_crud_accounts_opened_total",
+			Help: "Total number of bank accounts opened.",
+		}),
+		loginsFailed: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Name: "grpc_crud_logins_failed_total",
+			Help: "Total number of failed logins, by reason.",
+		}, []string{"reason"}),
+	}
+
+	m.registry.MustRegister(
+		collectors.NewGoCollector(),
+		collectors.NewProcessCollector(collectors.ProcessCollectorOpts{}),
+		m.started,
+		m.handled,
+		m.duration,
+		m.panics,
+		m.customersCreated,
+		m.accountsOpened,
+		m.loginsFailed,
+	)
+
+	return m
+}
+
+// RegisterDBStats exports the connection pool statistics of db
+func (m *Metrics) RegisterDBStats(db *sql.DB, dbName string) {
+	m.registry.MustRegister(collectors.NewDBStatsCollector(db, dbName))
+}
+
+// Handler serves the metrics in the Prometheus exposition format
+func (m *Metrics) Handler() http.Handler {
+	return promhttp.HandlerFor(m.registry, promhttp.HandlerOpts{})
+}
+
+// RPCStarted counts an RPC that has started
+func (m *Metrics) RPCStarted(fullMethod string) {
+	service, method := splitMethod(fullMethod)
+	m.started.WithLabelValues(service, method).Inc()
+}
+
+// RPCHandled records the outcome and latency of a finished RPC
+func (m *Metrics) RPCHandled(fullMethod string, code codes.Code, elapsed time.Duration) {
+	service, method := splitMethod(fullMethod)
+	m.handled.WithLabelValues(service, method, code.String()).Inc()
+	m.duration.WithLabelValues(service, method).Observe(elapsed.Seconds())
+}
+
+// PanicRecovered counts a panic recovered while handling an RPC
+func (m *Metrics) PanicRecovered(fullMethod string) {
+	service, method := splitMethod(fullMethod)
+	m.panics.WithLabelValues(service, method).Inc()
+}
+
+// CustomerCreated counts a new customer
+func (m *Metrics) CustomerCreated() {
+	m.customersCreated.Inc()
+}
+
+// AccountOpened counts a new bank account
+func (m *Metrics) AccountOpened() {
+	m.accountsOpened.Inc()
+}
+
+// LoginFailed counts a failed login
+func (m *Metrics) LoginFailed(reason string) {
+	m.loginsFailed.WithLabelValues(reason).Inc()
+}
+
+// splitMethod splits "/package.Service/Method" into service and method
+func splitMethod(fullMethod string) (string, string) {
+	fullMethod = strings.TrimPrefix(fullMethod, "/")
+	if i := strings.LastIndex(fullMethod, "/"); i >= 0 {
+		return fullMethod[:i], fullMethod[i+1:]
+	}
+	return "unknown", fullMethod
+}
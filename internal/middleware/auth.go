@@ -19,57 +19,77 @@ func AuthInterceptor(authService service.IAuthService) grpc.UnaryServerIntercept
 		info *grpc.UnaryServerInfo,
 		handler grpc.UnaryHandler,
 	) (interface{}, error) {
-
-		// Skip authentication for public methods
-		if isPublicMethod(info.FullMethod) {
-			return handler(ctx, req)
+		ctx, err := authenticate(ctx, authService, info.FullMethod)
+		if err != nil {
+			return nil, err
 		}
 
-		// Extract metadata from incoming context
-		md, ok := metadata.FromIncomingContext(ctx)
-		if !ok {
-			return nil, status.Error(codes.Unauthenticated, "missing metadata")
-		}
+		// Continue request
+		return handler(ctx, req)
+	}
+}
 
-		// Get Authorization header
-		authHeaders := md.Get("authorization")
-		if len(authHeaders) == 0 {
-			return nil, status.Error(codes.Unauthenticated, "missing authorization token")
+// StreamAuthInterceptor validates JWT tokens for protected streaming endpoints
+func StreamAuthInterceptor(authService service.IAuthService) grpc.StreamServerInterceptor {
+	return func(
+		srv interface{},
+		ss grpc.ServerStream,
+		info *grpc.StreamServerInfo,
+		handler grpc.StreamHandler,
+	) error {
+		ctx, err := authenticate(ss.Context(), authService, info.FullMethod)
+		if err != nil {
+			return err
 		}
 
-		// Expect "Bearer <token>"
-		token := authHeaders[0]
-		if !strings.HasPrefix(token, "Bearer ") {
-			return nil, status.Error(
-				codes.Unauthenticated,
-				"invalid authorization format, expected 'Bearer <token>'",
-			)
-		}
+		return handler(srv, wrapServerStream(ss, ctx))
+	}
+}
 
-		token = strings.TrimPrefix(token, "Bearer ")
+// authenticate validates the bearer token and returns a context carrying
+// the caller. Public methods pass through unchanged.
+func authenticate(ctx context.Context, authService service.IAuthService, method string) (context.Context, error) {
+	// Skip authentication for public methods
+	if isPublicMethod(method) {
+		return ctx, nil
+	}
 
-		// Validate token 
-		claims, err := authService.ValidateToken(token)
-		if err != nil {
-			return nil, status.Error(codes.Unauthenticated, "invalid or expired token")
-		}
+	// Extract metadata from incoming context
+	md, ok := metadata.FromIncomingContext(ctx)
+	if !ok {
+		return nil, status.Error(codes.Unauthenticated, "missing metadata")
+	}
 
-		// Add user info to context
-		ctx = context.WithValue(ctx, "user_id", claims.UserID)
-		ctx = context.WithValue(ctx, "email", claims.Email)
-		ctx = context.WithValue(ctx, "username", claims.Username)
+	// Get Authorization header
+	authHeaders := md.Get("authorization")
+	if len(authHeaders) == 0 {
+		return nil, status.Error(codes.Unauthenticated, "missing authorization token")
+	}
 
-		// Continue request
-		return handler(ctx, req)
+	// Expect "Bearer <token>"
+	token := authHeaders[0]
+	if !strings.HasPrefix(token, "Bearer ") {
+		return nil, status.Error(
+			codes.Unauthenticated,
+			"invalid authorization format, expected 'Bearer <token>'",
+		)
 	}
-}
 
-// isPublicMethod checks if the gRPC method does not require authentication
-func isPublicMethod(method string) bool {
-	publicMethods := map[string]bool{
-		"/grpc_crud.LoginService/Register": true,
-		"/grpc_crud.LoginService/Login":    true,
+	token = strings.TrimPrefix(token, "Bearer ")
+
+	// Validate token; refresh tokens are not accepted as bearer tokens
+	claims, err := authService.ValidateAccessToken(ctx, token)
+	if err != nil {
+		return nil, status.Error(codes.Unauthenticated, "invalid or expired token")
 	}
 
-	return publicMethods[method]
+	// Add user info to context
+	ctx = context.WithValue(ctx, "user_id", claims.UserID)
+	ctx = context.WithValue(ctx, "email", claims.Email)
+	ctx = context.WithValue(ctx, "username", claims.Username)
+	ctx = context.WithValue(ctx, "role", claims.Role)
+	ctx = context.WithValue(ctx, "customer_id", claims.CustomerID)
+	ctx = annotateRequestLog(ctx)
+
+	return ctx, nil
 }
@@ -0,0 +1,117 @@
+package middleware
+
+import (
+	"context"
+	"testing"
+
+	"github.com/golang-jwt/jwt/v5"
+	"github.com/paudelanil/grpc-crud/internal/service"
+	"github.com/paudelanil/grpc-crud/internal/service/authtest"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+)
+
+// protectedMethod is open to every role but needs a bearer token
+const protectedMethod = "/grpc_crud.AccountService/GetAccount"
+
+// forgeAccessToken re-signs the claims of token with the access key after
+// overriding some of them
+func forgeAccessToken(t *testing.T, token string, overrides jwt.MapClaims) string {
+	t.Helper()
+	return authtest.Sign(t, service.TokenTypeAccess, authtest.WithClaims(authtest.ClaimsOf(t, token), overrides))
+}
+
+// withBearer returns an incoming context carrying the token
+func withBearer(token string) context.Context {
+	return metadata.NewIncomingContext(context.Background(), metadata.Pairs("authorization", "Bearer "+token))
+}
+
+func TestAuthInterceptor(t *testing.T) {
+	authService, login := authtest.NewService(t)
+
+	tests := []struct {
+		name     string
+		token    string
+		wantCode codes.Code
+	}{
+		{"access token", login.AccessToken, codes.OK},
+		{"refresh token", login.RefreshToken, codes.Unauthenticated},
+		{"refresh audience", forgeAccessToken(t, login.AccessToken, jwt.MapClaims{"aud": []string{"grpc-crud/token-refresh"}}), codes.Unauthenticated},
+		{"unknown audience", forgeAccessToken(t, login.AccessToken, jwt.MapClaims{"aud": []string{"someone-else"}}), codes.Unauthenticated},
+		{"refresh type", forgeAccessToken(t, login.AccessToken, jwt.MapClaims{"typ": service.TokenTypeRefresh}), codes.Unauthenticated},
+	}
+
+	interceptor := AuthInterceptor(authService)
+	info := &grpc.UnaryServerInfo{FullMethod: protectedMethod}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			called := false
+			handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+				called = true
+				return nil, nil
+			}
+
+			_, err := interceptor(withBearer(tt.token), nil, info, handler)
+			if code := status.Code(err); code != tt.wantCode {
+				t.Fatalf("code = %v, want %v (err: %v)", code, tt.wantCode, err)
+			}
+			if called != (tt.wantCode == codes.OK) {
+				t.Fatalf("handler called = %v, want %v", called, tt.wantCode == codes.OK)
+			}
+		})
+	}
+}
+
+// fakeServerStream is a grpc.ServerStream that only carries a context
+type fakeServerStream struct {
+	grpc.ServerStream
+	ctx context.Context
+}
+
+func (s *fakeServerStream) Context() context.Context {
+	return s.ctx
+}
+
+func TestStreamAuthInterceptor(t *testing.T) {
+	authService, login := authtest.NewService(t)
+
+	tests := []struct {
+		name     string
+		ctx      context.Context
+		wantCode codes.Code
+	}{
+		{"no metadata", context.Background(), codes.Unauthenticated},
+		{"no bearer token", metadata.NewIncomingContext(context.Background(), metadata.Pairs("x-request-id", "1")), codes.Unauthenticated},
+		{"refresh token", withBearer(login.RefreshToken), codes.Unauthenticated},
+		{"access token", withBearer(login.AccessToken), codes.OK},
+	}
+
+	interceptor := StreamAuthInterceptor(authService)
+	info := &grpc.StreamServerInfo{FullMethod: "/grpc_crud.AccountStatementService/StreamStatement", IsServerStream: true}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var user *UserContext
+			handler := func(srv interface{}, ss grpc.ServerStream) error {
+				var err error
+				user, err = GetUserFromContext(ss.Context())
+				return err
+			}
+
+			err := interceptor(nil, &fakeServerStream{ctx: tt.ctx}, info, handler)
+			if code := status.Code(err); code != tt.wantCode {
+				t.Fatalf("code = %v, want %v (err: %v)", code, tt.wantCode, err)
+			}
+			if tt.wantCode != codes.OK {
+				if user != nil {
+					t.Fatal("handler ran for a rejected stream")
+				}
+				return
+			}
+			if user == nil || user.UserID != authtest.UserID || user.Role != authtest.Role {
+				t.Fatalf("stream context user = %+v, want user %s with role %s", user, authtest.UserID, authtest.Role)
+			}
+		})
+	}
+}
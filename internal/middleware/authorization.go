@@ -0,0 +1,150 @@
+package middleware
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/paudelanil/grpc-crud/models"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// methodPolicy describes who may call a gRPC method
+type methodPolicy struct {
+	Public bool     // no authentication required
+	Roles  []string // roles allowed to call the method when not public
+}
+
+// Role sets shared by the policy table
+var (
+	allRoles   = []string{models.RoleAdmin, models.RoleTeller, models.RoleAuditor, models.RoleCustomer}
+	staffRoles = []string{models.RoleAdmin, models.RoleTeller}
+	readRoles  = []string{models.RoleAdmin, models.RoleTeller, models.RoleAuditor}
+	adminRoles = []string{models.RoleAdmin}
+)
+
+// methodPolicies is the access policy for every RPC. Methods that are not
+// listed are denied to everyone. Where customers are allowed, the handler
+// scopes them to their own data.
+var methodPolicies = map[string]methodPolicy{
+	"/grpc.health.v1.Health/Check": {Public: true},
+	"/grpc.health.v1.Health/List":  {Public: true},
+	"/grpc.health.v1.Health/Watch": {Public: true},
+
+	// Reflection only describes the API, so tools such as grpcurl work
+	// before logging in
+	"/grpc.reflection.v1.ServerReflection/ServerReflectionInfo":      {Public: true},
+	"/grpc.reflection.v1alpha.ServerReflection/ServerReflectionInfo": {Public: true},
+
+	"/grpc_crud.LoginService/Register":       {Public: true},
+	"/grpc_crud.LoginService/Login":          {Public: true},
+	"/grpc_crud.LoginService/RefreshToken":   {Public: true}, // authenticated by the refresh token itself
+	"/grpc_crud.LoginService/Logout":         {Roles: allRoles},
+	"/grpc_crud.LoginService/UpdateUserRole": {Roles: adminRoles},
+	"/grpc_crud.LoginService/LinkCustomer":   {Roles: staffRoles},
+
+	"/grpc_crud.AccountService/CreateUser":    {Roles: staffRoles},
+	"/grpc_crud.AccountService/GetUser":       {Roles: allRoles},
+	"/grpc_crud.AccountService/UpdateUser":    {Roles: staffRoles},
+	"/grpc_crud.AccountService/DeleteUser":    {Roles: adminRoles},
+	"/grpc_crud.AccountService/ListUsers":     {Roles: readRoles},
+	"/grpc_crud.AccountService/CreateAccount": {Roles: staffRoles},
+	"/grpc_crud.AccountService/GetAccount":    {Roles: allRoles},
+	"/grpc_crud.AccountService/UpdateAccount": {Roles: staffRoles},
+	"/grpc_crud.AccountService/DeleteAccount": {Roles: adminRoles},
+	"/grpc_crud.AccountService/ListAccounts":  {Roles: allRoles},
+	"/grpc_crud.AccountService/Deposit":       {Roles: staffRoles},
+	"/grpc_crud.AccountService/Withdraw":      {Roles: staffRoles},
+	"/grpc_crud.AccountService/Transfer":      {Roles: staffRoles},
+
+	"/grpc_crud.AccountService/FreezeAccount":           {Roles: staffRoles},
+	"/grpc_crud.AccountService/UnfreezeAccount":         {Roles: staffRoles},
+	"/grpc_crud.AccountService/CloseAccount":            {Roles: staffRoles},
+	"/grpc_crud.AccountService/GetAccountStatusHistory": {Roles: readRoles},
+
+	// Customers may read statements of their own accounts only
+	"/grpc_crud.AccountStatementService/GetStatement":    {Roles: allRoles},
+	"/grpc_crud.AccountStatementService/StreamStatement": {Roles: allRoles},
+	"/grpc_crud.AccountStatementService/ExportStatement": {Roles: allRoles},
+
+	"/grpc_crud.ProductService/CreateProduct": {Roles: adminRoles},
+	"/grpc_crud.ProductService/GetProduct":    {Roles: allRoles},
+	"/grpc_crud.ProductService/UpdateProduct": {Roles: adminRoles},
+	"/grpc_crud.ProductService/ListProducts":  {Roles: allRoles},
+}
+
+// AuthorizationInterceptor checks the caller's role against methodPolicies.
+// It must run after AuthInterceptor.
+func AuthorizationInterceptor() grpc.UnaryServerInterceptor {
+	return func(
+		ctx context.Context,
+		req interface{},
+		info *grpc.UnaryServerInfo,
+		handler grpc.UnaryHandler,
+	) (interface{}, error) {
+		if err := authorize(ctx, info.FullMethod); err != nil {
+			return nil, err
+		}
+
+		return handler(ctx, req)
+	}
+}
+
+// StreamAuthorizationInterceptor checks the caller's role against
+// methodPolicies for streaming RPCs. It must run after StreamAuthInterceptor.
+func StreamAuthorizationInterceptor() grpc.StreamServerInterceptor {
+	return func(
+		srv interface{},
+		ss grpc.ServerStream,
+		info *grpc.StreamServerInfo,
+		handler grpc.StreamHandler,
+	) error {
+		if err := authorize(ss.Context(), info.FullMethod); err != nil {
+			return err
+		}
+
+		return handler(srv, ss)
+	}
+}
+
+// authorize checks that the authenticated caller may call the method
+func authorize(ctx context.Context, method string) error {
+	if isPublicMethod(method) {
+		return nil
+	}
+
+	user, err := GetUserFromContext(ctx)
+	if err != nil {
+		return err
+	}
+
+	if !isAllowed(method, user.Role) {
+		return status.Error(
+			codes.PermissionDenied,
+			fmt.Sprintf("role %q is not allowed to call %s", user.Role, method),
+		)
+	}
+
+	return nil
+}
+
+// isPublicMethod checks if the gRPC method does not require authentication
+func isPublicMethod(method string) bool {
+	return methodPolicies[method].Public
+}
+
+// isAllowed checks if the role may call the gRPC method
+func isAllowed(method, role string) bool {
+	policy, ok := methodPolicies[method]
+	if !ok {
+		return false
+	}
+
+	for _, allowed := range policy.Roles {
+		if allowed == role {
+			return true
+		}
+	}
+	return false
+}
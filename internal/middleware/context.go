@@ -9,9 +9,11 @@ import (
 
 // UserContext holds user information from the JWT token
 type UserContext struct {
-	UserID   string
-	Username string
-	Email    string
+	UserID     string
+	Username   string
+	Email      string
+	Role       string
+	CustomerID string // set when the user is linked to a bank customer
 }
 
 // GetUserFromContext extracts user information from the context
@@ -23,10 +25,14 @@ func GetUserFromContext(ctx context.Context) (*UserContext, error) {
 
 	username, _ := ctx.Value("username").(string)
 	email, _ := ctx.Value("email").(string)
+	role, _ := ctx.Value("role").(string)
+	customerID, _ := ctx.Value("customer_id").(string)
 
 	return &UserContext{
-		UserID:   userID,
-		Username: username,
-		Email:    email,
+		UserID:     userID,
+		Username:   username,
+		Email:      email,
+		Role:       role,
+		CustomerID: customerID,
 	}, nil
 }
@@ -0,0 +1,172 @@
+package middleware
+
+import (
+	"context"
+	"errors"
+	"net"
+
+	"github.com/paudelanil/grpc-crud/internal/logging"
+	"github.com/paudelanil/grpc-crud/internal/service"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/proto"
+)
+
+// IdempotencyKeyHeader is the metadata key clients send to make a mutating request safe to retry
+const IdempotencyKeyHeader = "idempotency-key"
+
+// maxIdempotencyKeyLength matches the idempotency_records.idempotency_key column
+const maxIdempotencyKeyLength = 255
+
+// IdempotencyInterceptor replays the stored response when a mutating request
+// is retried with the same idempotency key. It must run after AuthInterceptor
+// so keys are scoped to the calling user; keys sent to public methods are
+// scoped to the caller's network address instead.
+func IdempotencyInterceptor(idempotencyService service.IIdempotencyService) grpc.UnaryServerInterceptor {
+	return func(
+		ctx context.Context,
+		req interface{},
+		info *grpc.UnaryServerInfo,
+		handler grpc.UnaryHandler,
+	) (interface{}, error) {
+
+		if !isIdempotentMethod(info.FullMethod) {
+			return handler(ctx, req)
+		}
+
+		// The header is optional; without it the request runs as usual
+		md, _ := metadata.FromIncomingContext(ctx)
+		keys := md.Get(IdempotencyKeyHeader)
+		if len(keys) == 0 || keys[0] == "" {
+			return handler(ctx, req)
+		}
+		key := keys[0]
+		if len(key) > maxIdempotencyKeyLength {
+			return nil, status.Error(codes.InvalidArgument, "idempotency key is too long")
+		}
+
+		msg, ok := req.(proto.Message)
+		if !ok {
+			return handler(ctx, req)
+		}
+
+		scope := idempotencyScope(ctx)
+		if scope == "" {
+			return handler(ctx, req)
+		}
+
+		replay, recordID, err := idempotencyService.Begin(ctx, scope, key, info.FullMethod, msg)
+		if err != nil {
+			var replayed *service.ReplayedFailureError
+			switch {
+			case errors.As(err, &replayed):
+				return nil, status.ErrorProto(replayed.Status)
+			case errors.Is(err, service.ErrIdempotencyKeyReused):
+				return nil, status.Error(codes.FailedPrecondition, err.Error())
+			case errors.Is(err, service.ErrIdempotencyInProgress):
+				// Aborted tells the client to back off and retry
+				return nil, status.Error(codes.Aborted, err.Error())
+			default:
+				return nil, status.Error(codes.Internal, "failed to check idempotency key")
+			}
+		}
+		if replay != nil {
+			return replay, nil
+		}
+
+		// Record the outcome even if the client has gone away in the meantime
+		storeCtx := context.WithoutCancel(ctx)
+		abandon := func() {
+			if abandonErr := idempotencyService.Abandon(storeCtx, recordID); abandonErr != nil {
+				logging.FromContext(ctx).ErrorContext(ctx, "failed to release idempotency key", "error", abandonErr)
+			}
+		}
+
+		// Release the key of a panicking handler before RecoveryInterceptor,
+		// further out, turns the panic into an error
+		defer func() {
+			if r := recover(); r != nil {
+				abandon()
+				panic(r)
+			}
+		}()
+
+		resp, err := handler(ctx, req)
+		if err != nil {
+			if failedBeforeWrite(err) {
+				abandon()
+			} else if failErr := idempotencyService.Fail(storeCtx, recordID, status.Convert(err).Proto()); failErr != nil {
+				logging.FromContext(ctx).ErrorContext(ctx, "failed to store idempotent error", "error", failErr)
+			}
+			return nil, err
+		}
+
+		if out, ok := resp.(proto.Message); ok {
+			if completeErr := idempotencyService.Complete(storeCtx, recordID, out); completeErr != nil {
+				logging.FromContext(ctx).ErrorContext(ctx, "failed to store idempotent response", "error", completeErr)
+			}
+		}
+
+		return resp, nil
+	}
+}
+
+// idempotencyScope returns the scope of the caller's idempotency keys: the
+// user ID, or for public methods "peer:" and the caller's host, so anonymous
+// callers cannot see each other's keys. It is empty when neither is known,
+// and the request then runs without idempotency.
+func idempotencyScope(ctx context.Context) string {
+	if user, err := GetUserFromContext(ctx); err == nil {
+		return user.UserID
+	}
+
+	address := peerAddress(ctx)
+	if address == "" {
+		return ""
+	}
+	// Retries may come over a new connection, from another port
+	if host, _, err := net.SplitHostPort(address); err == nil {
+		address = host
+	}
+	return "peer:" + address
+}
+
+// failedBeforeWrite reports whether an error is one that services only
+// return before writing anything, or from a write transaction that rolled
+// back, so the key can be released for a retry. Any other error, such as
+// Internal or DeadlineExceeded, may follow a commit; it is stored and
+// replayed so a retry cannot apply the request twice.
+func failedBeforeWrite(err error) bool {
+	switch status.Code(err) {
+	case codes.InvalidArgument, codes.NotFound, codes.AlreadyExists, codes.FailedPrecondition,
+		codes.OutOfRange, codes.PermissionDenied, codes.Unauthenticated:
+		return true
+	default:
+		return false
+	}
+}
+
+// isIdempotentMethod checks if the gRPC method mutates state and honours idempotency keys
+func isIdempotentMethod(method string) bool {
+	idempotentMethods := map[string]bool{
+		"/grpc_crud.LoginService/Register":          true,
+		"/grpc_crud.AccountService/CreateUser":      true,
+		"/grpc_crud.AccountService/UpdateUser":      true,
+		"/grpc_crud.AccountService/DeleteUser":      true,
+		"/grpc_crud.AccountService/CreateAccount":   true,
+		"/grpc_crud.AccountService/UpdateAccount":   true,
+		"/grpc_crud.AccountService/DeleteAccount":   true,
+		"/grpc_crud.AccountService/Deposit":         true,
+		"/grpc_crud.AccountService/Withdraw":        true,
+		"/grpc_crud.AccountService/Transfer":        true,
+		"/grpc_crud.AccountService/FreezeAccount":   true,
+		"/grpc_crud.AccountService/UnfreezeAccount": true,
+		"/grpc_crud.AccountService/CloseAccount":    true,
+		"/grpc_crud.ProductService/CreateProduct":   true,
+		"/grpc_crud.ProductService/UpdateProduct":   true,
+	}
+
+	return idempotentMethods[method]
+}
@@ -0,0 +1,40 @@
+package middleware
+
+import (
+	"context"
+	"net"
+	"testing"
+
+	"google.golang.org/grpc/peer"
+)
+
+// withPeer returns a context of a caller at address
+func withPeer(address string) context.Context {
+	addr, err := net.ResolveTCPAddr("tcp", address)
+	if err != nil {
+		panic(err)
+	}
+	return peer.NewContext(context.Background(), &peer.Peer{Addr: addr})
+}
+
+func TestIdempotencyScopeOfAnonymousCallers(t *testing.T) {
+	tests := []struct {
+		name string
+		ctx  context.Context
+		want string
+	}{
+		{"no peer", context.Background(), ""},
+		{"ipv4", withPeer("192.0.2.1:50000"), "peer:192.0.2.1"},
+		{"another port", withPeer("192.0.2.1:50001"), "peer:192.0.2.1"},
+		{"another host", withPeer("192.0.2.2:50000"), "peer:192.0.2.2"},
+		{"ipv6", withPeer("[2001:db8::1]:50000"), "peer:2001:db8::1"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := idempotencyScope(tt.ctx); got != tt.want {
+				t.Fatalf("idempotencyScope = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
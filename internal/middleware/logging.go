@@ -2,42 +2,165 @@ package middleware
 
 import (
 	"context"
-	"log"
+	"log/slog"
 	"time"
 
+	"github.com/paudelanil/grpc-crud/internal/logging"
 	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/peer"
 	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/proto"
 )
 
-// LoggingInterceptor logs all incoming gRPC requests
-func LoggingInterceptor() grpc.UnaryServerInterceptor {
+// RequestIDHeader carries the request ID in request and response metadata
+const RequestIDHeader = "x-request-id"
+
+// maxRequestIDLength bounds client supplied request IDs
+const maxRequestIDLength = 128
+
+// requestLog is the log record of one request. userID is filled in by the
+// auth interceptor further down the chain.
+type requestLog struct {
+	requestID string
+	start     time.Time
+	logger    *slog.Logger
+	userID    string
+}
+
+type requestLogKey struct{}
+
+// LoggingInterceptor logs every request as one structured record. It
+// assigns the request ID, echoes it in the response headers and puts a
+// request scoped logger in the context for the handlers, services and
+// repositories.
+func LoggingInterceptor(logger *slog.Logger) grpc.UnaryServerInterceptor {
 	return func(
 		ctx context.Context,
 		req interface{},
 		info *grpc.UnaryServerInfo,
 		handler grpc.UnaryHandler,
 	) (interface{}, error) {
-		start := time.Now()
+		ctx, entry := beginRequestLog(ctx, logger, info.FullMethod)
+		_ = grpc.SetHeader(ctx, metadata.Pairs(RequestIDHeader, entry.requestID))
 
-		// Log the incoming request
-		log.Printf("[gRPC] --> Method: %s | Started at: %s", info.FullMethod, start.Format(time.RFC3339))
+		if msg, ok := req.(proto.Message); ok && entry.logger.Enabled(ctx, slog.LevelDebug) {
+			entry.logger.DebugContext(ctx, "request received", "request", logging.Proto(msg))
+		}
 
 		// Call the handler to complete the RPC
 		resp, err := handler(ctx, req)
 
-		// Calculate duration
-		duration := time.Since(start)
-
-		// Log the response
-		if err != nil {
-			st, _ := status.FromError(err)
-			log.Printf("Method: %s | Duration: %v | Status: %s | Error: %v",
-				info.FullMethod, duration, st.Code(), err)
-		} else {
-			log.Printf(" Method: %s | Duration: %v | Status: OK",
-				info.FullMethod, duration)
+		entry.finish(ctx, err)
+		return resp, err
+	}
+}
+
+// StreamLoggingInterceptor logs every streaming RPC as one structured
+// record when the stream ends
+func StreamLoggingInterceptor(logger *slog.Logger) grpc.StreamServerInterceptor {
+	return func(
+		srv interface{},
+		ss grpc.ServerStream,
+		info *grpc.StreamServerInfo,
+		handler grpc.StreamHandler,
+	) error {
+		ctx, entry := beginRequestLog(ss.Context(), logger, info.FullMethod)
+		_ = ss.SetHeader(metadata.Pairs(RequestIDHeader, entry.requestID))
+
+		err := handler(srv, wrapServerStream(ss, ctx))
+
+		entry.finish(ctx, err)
+		return err
+	}
+}
+
+// beginRequestLog assigns the request ID and returns a context carrying the
+// request scoped logger
+func beginRequestLog(ctx context.Context, logger *slog.Logger, method string) (context.Context, *requestLog) {
+	entry := &requestLog{
+		requestID: incomingRequestID(ctx),
+		start:     time.Now(),
+	}
+	entry.logger = logger.With("request_id", entry.requestID, "method", method)
+
+	ctx = logging.WithRequestID(ctx, entry.requestID)
+	ctx = logging.WithLogger(ctx, entry.logger)
+	ctx = context.WithValue(ctx, requestLogKey{}, entry)
+	return ctx, entry
+}
+
+// finish writes the request record
+func (e *requestLog) finish(ctx context.Context, err error) {
+	code := status.Code(err)
+	attrs := []slog.Attr{
+		slog.String("code", code.String()),
+		slog.Float64("duration_ms", float64(time.Since(e.start).Microseconds())/1000),
+		slog.String("peer", peerAddress(ctx)),
+	}
+	if e.userID != "" {
+		attrs = append(attrs, slog.String("user_id", e.userID))
+	}
+	if err != nil {
+		attrs = append(attrs, slog.String("error", status.Convert(err).Message()))
+	}
+	e.logger.LogAttrs(ctx, levelForCode(code), "request finished", attrs...)
+}
+
+// annotateRequestLog records the authenticated user on the request log
+// record and the context logger
+func annotateRequestLog(ctx context.Context) context.Context {
+	user, err := GetUserFromContext(ctx)
+	if err != nil {
+		return ctx
+	}
+	if entry, ok := ctx.Value(requestLogKey{}).(*requestLog); ok {
+		entry.userID = user.UserID
+	}
+	return logging.With(ctx, "user_id", user.UserID)
+}
+
+// incomingRequestID returns the caller's request ID, or a new one when it
+// is missing or unusable
+func incomingRequestID(ctx context.Context) string {
+	if md, ok := metadata.FromIncomingContext(ctx); ok {
+		if values := md.Get(RequestIDHeader); len(values) > 0 && isValidRequestID(values[0]) {
+			return values[0]
+		}
+	}
+	return logging.NewRequestID()
+}
+
+// isValidRequestID accepts short printable ASCII IDs
+func isValidRequestID(id string) bool {
+	if id == "" || len(id) > maxRequestIDLength {
+		return false
+	}
+	for i := 0; i < len(id); i++ {
+		if id[i] < 0x21 || id[i] > 0x7e {
+			return false
 		}
+	}
+	return true
+}
 
-		return resp, err
+// peerAddress returns the caller's network address
+func peerAddress(ctx context.Context) string {
+	if p, ok := peer.FromContext(ctx); ok && p.Addr != nil {
+		return p.Addr.String()
+	}
+	return ""
+}
+
+// levelForCode logs server faults as errors and caller mistakes as warnings
+func levelForCode(code codes.Code) slog.Level {
+	switch code {
+	case codes.OK:
+		return slog.LevelInfo
+	case codes.Internal, codes.Unknown, codes.DataLoss, codes.Unavailable, codes.DeadlineExceeded, codes.Unimplemented:
+		return slog.LevelError
+	default:
+		return slog.LevelWarn
 	}
 }
@@ -0,0 +1,48 @@
+package middleware
+
+import (
+	"context"
+	"time"
+
+	"github.com/paudelanil/grpc-crud/internal/metrics"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/status"
+)
+
+// MetricsInterceptor records request counts, status codes and latency for
+// every RPC. It should run first so rejected requests are counted too.
+func MetricsInterceptor(m *metrics.Metrics) grpc.UnaryServerInterceptor {
+	return func(
+		ctx context.Context,
+		req interface{},
+		info *grpc.UnaryServerInfo,
+		handler grpc.UnaryHandler,
+	) (interface{}, error) {
+		start := time.Now()
+		m.RPCStarted(info.FullMethod)
+
+		resp, err := handler(ctx, req)
+
+		m.RPCHandled(info.FullMethod, status.Code(err), time.Since(start))
+		return resp, err
+	}
+}
+
+// StreamMetricsInterceptor records request counts, status codes and the
+// lifetime of every streaming RPC
+func StreamMetricsInterceptor(m *metrics.Metrics) grpc.StreamServerInterceptor {
+	return func(
+		srv interface{},
+		ss grpc.ServerStream,
+		info *grpc.StreamServerInfo,
+		handler grpc.StreamHandler,
+	) error {
+		start := time.Now()
+		m.RPCStarted(info.FullMethod)
+
+		err := handler(srv, ss)
+
+		m.RPCHandled(info.FullMethod, status.Code(err), time.Since(start))
+		return err
+	}
+}
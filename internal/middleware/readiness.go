@@ -0,0 +1,51 @@
+package middleware
+
+import (
+	"context"
+	"strings"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// ReadinessInterceptor rejects requests with Unavailable until ready
+// reports true, so no RPC reaches the database before startup migrations
+// finish. Health checks and reflection are always served.
+func ReadinessInterceptor(ready func() bool) grpc.UnaryServerInterceptor {
+	return func(
+		ctx context.Context,
+		req interface{},
+		info *grpc.UnaryServerInfo,
+		handler grpc.UnaryHandler,
+	) (interface{}, error) {
+		if !ready() && !isAlwaysServed(info.FullMethod) {
+			return nil, status.Error(codes.Unavailable, "server is starting, try again shortly")
+		}
+
+		return handler(ctx, req)
+	}
+}
+
+// StreamReadinessInterceptor rejects streaming RPCs like ReadinessInterceptor
+func StreamReadinessInterceptor(ready func() bool) grpc.StreamServerInterceptor {
+	return func(
+		srv interface{},
+		ss grpc.ServerStream,
+		info *grpc.StreamServerInfo,
+		handler grpc.StreamHandler,
+	) error {
+		if !ready() && !isAlwaysServed(info.FullMethod) {
+			return status.Error(codes.Unavailable, "server is starting, try again shortly")
+		}
+
+		return handler(srv, ss)
+	}
+}
+
+// isAlwaysServed checks if the gRPC method belongs to the health or
+// reflection services, which work without the database schema
+func isAlwaysServed(method string) bool {
+	return strings.HasPrefix(method, "/grpc.health.v1.Health/") ||
+		strings.HasPrefix(method, "/grpc.reflection.")
+}
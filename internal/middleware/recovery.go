@@ -0,0 +1,103 @@
+package middleware
+
+import (
+	"context"
+	"fmt"
+	"runtime/debug"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/paudelanil/grpc-crud/internal/logging"
+	"github.com/paudelanil/grpc-crud/internal/reporting"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// PanicCounter counts recovered panics
+type PanicCounter interface {
+	PanicRecovered(fullMethod string)
+}
+
+// RecoveryInterceptor turns a panic in a handler or a later interceptor
+// into codes.Internal with an opaque error ID, so one bad request cannot
+// take down the server. The stack trace is logged and reported under that
+// ID. counter and reporter may be nil. It should run after the metrics and
+// logging interceptors so they record the failure.
+func RecoveryInterceptor(counter PanicCounter, reporter reporting.Reporter) grpc.UnaryServerInterceptor {
+	return func(
+		ctx context.Context,
+		req interface{},
+		info *grpc.UnaryServerInfo,
+		handler grpc.UnaryHandler,
+	) (resp interface{}, err error) {
+		defer func() {
+			if r := recover(); r != nil {
+				resp, err = nil, recoverPanic(ctx, info.FullMethod, r, counter, reporter)
+			}
+		}()
+
+		return handler(ctx, req)
+	}
+}
+
+// StreamRecoveryInterceptor turns a panic in a streaming handler into
+// codes.Internal, like RecoveryInterceptor
+func StreamRecoveryInterceptor(counter PanicCounter, reporter reporting.Reporter) grpc.StreamServerInterceptor {
+	return func(
+		srv interface{},
+		ss grpc.ServerStream,
+		info *grpc.StreamServerInfo,
+		handler grpc.StreamHandler,
+	) (err error) {
+		defer func() {
+			if r := recover(); r != nil {
+				err = recoverPanic(ss.Context(), info.FullMethod, r, counter, reporter)
+			}
+		}()
+
+		return handler(srv, ss)
+	}
+}
+
+// recoverPanic logs, counts and reports a recovered panic and returns the
+// error sent to the caller
+func recoverPanic(ctx context.Context, method string, value interface{}, counter PanicCounter, reporter reporting.Reporter) error {
+	report := reporting.Report{
+		ErrorID:   uuid.New().String(),
+		Time:      time.Now(),
+		Method:    method,
+		RequestID: logging.RequestID(ctx),
+		UserID:    requestUserID(ctx),
+		Message:   fmt.Sprint(value),
+		Stack:     string(debug.Stack()),
+	}
+
+	logger := logging.FromContext(ctx)
+	logger.ErrorContext(ctx, "recovered from panic",
+		"error_id", report.ErrorID,
+		"panic", report.Message,
+		"stack", report.Stack,
+	)
+
+	if counter != nil {
+		counter.PanicRecovered(method)
+	}
+	if reporter != nil {
+		if err := reporter.Report(context.WithoutCancel(ctx), report); err != nil {
+			logger.ErrorContext(ctx, "failed to report panic", "error_id", report.ErrorID, "error", err)
+		}
+	}
+
+	return status.Errorf(codes.Internal, "internal error, reference %s", report.ErrorID)
+}
+
+// requestUserID returns the authenticated user of the request, if the auth
+// interceptor has run. The recovery interceptor runs before it, so the
+// user is read from the request log record rather than the context.
+func requestUserID(ctx context.Context) string {
+	if entry, ok := ctx.Value(requestLogKey{}).(*requestLog); ok {
+		return entry.userID
+	}
+	return ""
+}
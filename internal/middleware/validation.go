@@ -0,0 +1,93 @@
+package middleware
+
+import (
+	"context"
+	"strings"
+
+	"github.com/paudelanil/grpc-crud/internal/validation"
+	"google.golang.org/genproto/googleapis/rpc/errdetails"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/proto"
+)
+
+// ValidationInterceptor rejects requests that break the validator's rules
+// with InvalidArgument, listing every violation in a BadRequest detail.
+// It should run after authorization, so callers learn nothing about a
+// method they may not call, and before idempotency, so invalid requests do
+// not use up their key.
+func ValidationInterceptor(validator *validation.Validator) grpc.UnaryServerInterceptor {
+	return func(
+		ctx context.Context,
+		req interface{},
+		info *grpc.UnaryServerInfo,
+		handler grpc.UnaryHandler,
+	) (interface{}, error) {
+		if msg, ok := req.(proto.Message); ok {
+			if err := validate(validator, msg); err != nil {
+				return nil, err
+			}
+		}
+
+		return handler(ctx, req)
+	}
+}
+
+// StreamValidationInterceptor validates every message received on a
+// stream, like ValidationInterceptor
+func StreamValidationInterceptor(validator *validation.Validator) grpc.StreamServerInterceptor {
+	return func(
+		srv interface{},
+		ss grpc.ServerStream,
+		info *grpc.StreamServerInfo,
+		handler grpc.StreamHandler,
+	) error {
+		return handler(srv, &validatingStream{ServerStream: ss, validator: validator})
+	}
+}
+
+// validatingStream validates each message as the handler receives it
+type validatingStream struct {
+	grpc.ServerStream
+	validator *validation.Validator
+}
+
+func (s *validatingStream) RecvMsg(m interface{}) error {
+	if err := s.ServerStream.RecvMsg(m); err != nil {
+		return err
+	}
+	if msg, ok := m.(proto.Message); ok {
+		return validate(s.validator, msg)
+	}
+	return nil
+}
+
+// validate returns an InvalidArgument status describing every violation,
+// or nil when the message is valid
+func validate(validator *validation.Validator, msg proto.Message) error {
+	violations := validator.Validate(msg)
+	if len(violations) == 0 {
+		return nil
+	}
+
+	descriptions := make([]string, len(violations))
+	badRequest := &errdetails.BadRequest{}
+	for i, v := range violations {
+		descriptions[i] = v.Description
+		badRequest.FieldViolations = append(badRequest.FieldViolations, &errdetails.BadRequest_FieldViolation{
+			Field:       v.Field,
+			Description: v.Description,
+		})
+	}
+
+	st := status.New(codes.InvalidArgument, strings.Join(descriptions, "; "))
+	detailed, err := st.WithDetails(&errdetails.ErrorInfo{
+		Reason: "INVALID_ARGUMENT",
+		Domain: "grpc-crud",
+	}, badRequest)
+	if err != nil {
+		return st.Err()
+	}
+	return detailed.Err()
+}
@@ -0,0 +1,116 @@
+package money
+
+import (
+	"errors"
+	"fmt"
+	"math/big"
+	"strconv"
+	"strings"
+)
+
+// Currency describes how amounts in a currency are stored and rounded
+type Currency struct {
+	Code     string
+	Exponent int // number of minor-unit digits, e.g. 2 for NPR paisa, 0 for JPY
+}
+
+// currencies lists the ISO 4217 currencies the bank can hold
+var currencies = map[string]Currency{
+	"NPR": {Code: "NPR", Exponent: 2},
+	"INR": {Code: "INR", Exponent: 2},
+	"USD": {Code: "USD", Exponent: 2},
+	"EUR": {Code: "EUR", Exponent: 2},
+	"GBP": {Code: "GBP", Exponent: 2},
+	"AUD": {Code: "AUD", Exponent: 2},
+	"CNY": {Code: "CNY", Exponent: 2},
+	"JPY": {Code: "JPY", Exponent: 0},
+	"KRW": {Code: "KRW", Exponent: 0},
+	"KWD": {Code: "KWD", Exponent: 3},
+	"BHD": {Code: "BHD", Exponent: 3},
+}
+
+// ErrUnsupportedCurrency is returned for currency codes the bank does not hold
+var ErrUnsupportedCurrency = errors.New("unsupported currency")
+
+// Lookup returns the currency for an ISO 4217 code
+func Lookup(code string) (Currency, error) {
+	currency, ok := currencies[strings.ToUpper(code)]
+	if !ok {
+		return Currency{}, fmt.Errorf("%w: %q", ErrUnsupportedCurrency, code)
+	}
+	return currency, nil
+}
+
+// IsSupported reports whether the currency code is known
+func IsSupported(code string) bool {
+	_, err := Lookup(code)
+	return err == nil
+}
+
+// ParseDecimal converts a decimal string such as "100.50" to minor units of
+// the currency. Digits beyond the currency exponent are rounded half to even.
+// It reads the amounts of the legacy numeric columns during migration.
+func ParseDecimal(code, amount string) (int64, error) {
+	currency, err := Lookup(code)
+	if err != nil {
+		return 0, err
+	}
+
+	r, ok := new(big.Rat).SetString(strings.TrimSpace(amount))
+	if !ok {
+		return 0, fmt.Errorf("invalid amount %q", amount)
+	}
+	return currency.fromRat(r)
+}
+
+// Format renders minor units as a decimal string, e.g. 10050 NPR as "100.50"
+func Format(code string, units int64) string {
+	currency, err := Lookup(code)
+	if err != nil {
+		return strconv.FormatInt(units, 10)
+	}
+	if currency.Exponent == 0 {
+		return strconv.FormatInt(units, 10)
+	}
+
+	sign := ""
+	if units < 0 {
+		sign = "-"
+	}
+	abs := new(big.Int).Abs(big.NewInt(units))
+	scale := new(big.Int).Exp(big.NewInt(10), big.NewInt(int64(currency.Exponent)), nil)
+	whole, frac := new(big.Int).QuoRem(abs, scale, new(big.Int))
+	return fmt.Sprintf("%s%s.%0*s", sign, whole.String(), currency.Exponent, frac.String())
+}
+
+// ToFloat converts minor units to a major-unit float for the deprecated
+// double fields. It must never be used for arithmetic.
+func ToFloat(code string, units int64) float64 {
+	f, _ := strconv.ParseFloat(Format(code, units), 64)
+	return f
+}
+
+// fromRat scales r to minor units and rounds half to even
+func (c Currency) fromRat(r *big.Rat) (int64, error) {
+	scale := new(big.Int).Exp(big.NewInt(10), big.NewInt(int64(c.Exponent)), nil)
+	scaled := new(big.Rat).Mul(r, new(big.Rat).SetInt(scale))
+
+	num, den := scaled.Num(), scaled.Denom()
+	quo, rem := new(big.Int).QuoRem(num, den, new(big.Int))
+
+	// Compare twice the remainder with the denominator to decide rounding
+	twice := new(big.Int).Mul(new(big.Int).Abs(rem), big.NewInt(2))
+	switch cmp := twice.Cmp(den); {
+	case cmp > 0, cmp == 0 && quo.Bit(0) == 1:
+		if num.Sign() < 0 {
+			quo.Sub(quo, big.NewInt(1))
+		} else {
+			quo.Add(quo, big.NewInt(1))
+		}
+	}
+
+	if !quo.IsInt64() {
+		return 0, fmt.Errorf("amount out of range for %s", c.Code)
+	}
+	return quo.Int64(), nil
+}
@@ -0,0 +1,104 @@
+package money
+
+import "testing"
+
+func TestParseDecimal(t *testing.T) {
+	tests := []struct {
+		code   string
+		amount string
+		want   int64
+	}{
+		{"NPR", "100.50", 10050},
+		{"NPR", "0", 0},
+		{"NPR", "-0.05", -5},
+		{"NPR", "12", 1200},
+
+		// Ties round to the even minor unit
+		{"NPR", "1.005", 100},
+		{"NPR", "1.015", 102},
+		{"NPR", "1.0051", 101},
+		{"NPR", "-1.005", -100},
+		{"NPR", "-1.015", -102},
+
+		{"JPY", "100", 100},
+		{"JPY", "100.5", 100},
+		{"JPY", "101.5", 102},
+		{"JPY", "-0.5", 0},
+
+		{"KWD", "1.2345", 1234},
+		{"KWD", "1.2355", 1236},
+		{"KWD", "-0.001", -1},
+
+		{"npr", "1.00", 100},
+	}
+
+	for _, tt := range tests {
+		got, err := ParseDecimal(tt.code, tt.amount)
+		if err != nil {
+			t.Errorf("ParseDecimal(%q, %q): %v", tt.code, tt.amount, err)
+			continue
+		}
+		if got != tt.want {
+			t.Errorf("ParseDecimal(%q, %q) = %d, want %d", tt.code, tt.amount, got, tt.want)
+		}
+	}
+}
+
+func TestParseDecimalRejects(t *testing.T) {
+	tests := []struct {
+		code   string
+		amount string
+	}{
+		{"XXX", "1.00"},
+		{"NPR", "abc"},
+		{"NPR", ""},
+		{"NPR", "100000000000000000000"},
+	}
+
+	for _, tt := range tests {
+		if got, err := ParseDecimal(tt.code, tt.amount); err == nil {
+			t.Errorf("ParseDecimal(%q, %q) = %d, want an error", tt.code, tt.amount, got)
+		}
+	}
+}
+
+func TestFormat(t *testing.T) {
+	tests := []struct {
+		code  string
+		units int64
+		want  string
+	}{
+		{"NPR", 10050, "100.50"},
+		{"NPR", 0, "0.00"},
+		{"NPR", 5, "0.05"},
+		{"NPR", -5, "-0.05"},
+		{"NPR", -10050, "-100.50"},
+
+		{"JPY", 100, "100"},
+		{"JPY", -100, "-100"},
+
+		{"KWD", 1234, "1.234"},
+		{"KWD", 1, "0.001"},
+		{"KWD", -1, "-0.001"},
+
+		// Unknown currencies show the raw minor units
+		{"XXX", 123, "123"},
+	}
+
+	for _, tt := range tests {
+		if got := Format(tt.code, tt.units); got != tt.want {
+			t.Errorf("Format(%q, %d) = %q, want %q", tt.code, tt.units, got, tt.want)
+		}
+	}
+}
+
+func TestFormatRoundTrips(t *testing.T) {
+	for _, code := range []string{"NPR", "JPY", "KWD"} {
+		for _, units := range []int64{0, 1, -1, 999, -1000, 123456789} {
+			got, err := ParseDecimal(code, Format(code, units))
+			if err != nil || got != units {
+				t.Errorf("ParseDecimal(%q, Format(%q, %d)) = %d, %v", code, code, units, got, err)
+			}
+		}
+	}
+}
@@ -0,0 +1,68 @@
+package reporting
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"sync"
+	"time"
+)
+
+// Report describes a server fault, such as a recovered panic
+type Report struct {
+	ErrorID   string    `json:"error_id"` // the ID returned to the caller
+	Time      time.Time `json:"time"`
+	Method    string    `json:"method"`
+	RequestID string    `json:"request_id,omitempty"`
+	UserID    string    `json:"user_id,omitempty"`
+	Message   string    `json:"message"`
+	Stack     string    `json:"stack,omitempty"`
+}
+
+// Reporter forwards fault reports to an error tracker
+type Reporter interface {
+	Report(ctx context.Context, report Report) error
+}
+
+// NopReporter discards all reports
+type NopReporter struct{}
+
+func (NopReporter) Report(context.Context, Report) error { return nil }
+
+// FileReporter appends reports to a local file, one JSON object per line.
+// It is meant for development and for hosts without an error tracker.
+type FileReporter struct {
+	mu   sync.Mutex
+	file *os.File
+}
+
+// NewFileReporter opens, or creates, the report file at path
+func NewFileReporter(path string) (*FileReporter, error) {
+	file, err := os.OpenFile(path, os.O_WRONLY|os.O_APPEND|os.O_CREATE, 0o600)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open error report file: %w", err)
+	}
+	return &FileReporter{file: file}, nil
+}
+
+// Report writes the report as one line
+func (r *FileReporter) Report(ctx context.Context, report Report) error {
+	line, err := json.Marshal(report)
+	if err != nil {
+		return err
+	}
+	line = append(line, '\n')
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	_, err = r.file.Write(line)
+	return err
+}
+
+// Close closes the report file
+func (r *FileReporter) Close() error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return r.file.Close()
+}
@@ -3,20 +3,37 @@ package repository
 import (
 	"context"
 	"errors"
+	"time"
 
 	"github.com/paudelanil/grpc-crud/models"
 	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
 )
 
+// StatusGuard inspects the locked account before its status changes;
+// returning an error leaves the account unchanged
+type StatusGuard func(account *models.Account) error
+
+// AccountUpdate changes the locked account before it is saved; returning
+// an error leaves the account unchanged
+type AccountUpdate func(account *models.Account) error
+
 // IAccountRepository defines the interface for account data operations
 type IAccountRepository interface {
 	Create(ctx context.Context, account *models.Account) error
 	FindByID(ctx context.Context, id string) (*models.Account, error)
-	FindByCustomerID(ctx context.Context, customerID string) ([]*models.Account, error)
-	FindAll(ctx context.Context, limit, offset int) ([]*models.Account, error)
-	Update(ctx context.Context, account *models.Account) error
-	Delete(ctx context.Context, id string) error
-	IsAccountNumberTaken(ctx context.Context, accountNumber string) (bool, error)
+	FindByAccountNumber(ctx context.Context, accountNumber string) (*models.Account, error)
+	FindByCustomerID(ctx context.Context, customerID string, page PageQuery) ([]*models.Account, error)
+	FindAll(ctx context.Context, page PageQuery) ([]*models.Account, error)
+	Count(ctx context.Context) (int64, error)
+	EstimateCount(ctx context.Context) (int64, error)
+	CountByCustomerID(ctx context.Context, customerID string) (int64, error)
+	Update(ctx context.Context, accountID string, update AccountUpdate, change *models.AccountStatusChange) (*models.Account, error)
+	Delete(ctx context.Context, id string, guard StatusGuard) error
+	ChangeStatus(ctx context.Context, change *models.AccountStatusChange, guard StatusGuard) (*models.Account, error)
+	FindStatusHistory(ctx context.Context, accountID string) ([]*models.AccountStatusChange, error)
+	NextAccountSerial(ctx context.Context) (int64, error)
+	CountByCustomerAndType(ctx context.Context, customerID, accountType string) (int64, error)
 }
 
 // AccountRepository implements IAccountRepository interface
@@ -32,7 +49,7 @@ func NewAccountRepository(db *gorm.DB) IAccountRepository {
 // Create creates a new account in the database
 func (r *AccountRepository) Create(ctx context.Context, account *models.Account) error {
 	result := r.db.WithContext(ctx).Create(account)
-	return result.Error
+	return translateError(result.Error)
 }
 
 // FindByID finds an account by ID
@@ -41,57 +58,178 @@ func (r *AccountRepository) FindByID(ctx context.Context, id string) (*models.Ac
 	result := r.db.WithContext(ctx).Where("account_id = ?", id).First(&account)
 	if result.Error != nil {
 		if errors.Is(result.Error, gorm.ErrRecordNotFound) {
-			return nil, errors.New("account not found")
+			return nil, notFound("account")
+		}
+		return nil, result.Error
+	}
+	return &account, nil
+}
+
+// FindByAccountNumber finds an account by account number
+func (r *AccountRepository) FindByAccountNumber(ctx context.Context, accountNumber string) (*models.Account, error) {
+	var account models.Account
+	result := r.db.WithContext(ctx).Where("account_number = ?", accountNumber).First(&account)
+	if result.Error != nil {
+		if errors.Is(result.Error, gorm.ErrRecordNotFound) {
+			return nil, notFound("account")
 		}
 		return nil, result.Error
 	}
 	return &account, nil
 }
 
-// FindByCustomerID finds all accounts for a specific customer
-func (r *AccountRepository) FindByCustomerID(ctx context.Context, customerID string) ([]*models.Account, error) {
+// FindByCustomerID retrieves one page of the accounts of a specific customer, oldest first
+func (r *AccountRepository) FindByCustomerID(ctx context.Context, customerID string, page PageQuery) ([]*models.Account, error) {
 	var accounts []*models.Account
-	result := r.db.WithContext(ctx).Where("customer_id = ?", customerID).Find(&accounts)
+	query := r.db.WithContext(ctx).Where("customer_id = ?", customerID)
+	result := paginate(query, "created_at", "account_id", page).Find(&accounts)
 	if result.Error != nil {
 		return nil, result.Error
 	}
 	return accounts, nil
 }
 
-// FindAll retrieves all accounts with pagination
-func (r *AccountRepository) FindAll(ctx context.Context, limit, offset int) ([]*models.Account, error) {
+// FindAll retrieves one page of accounts, oldest first
+func (r *AccountRepository) FindAll(ctx context.Context, page PageQuery) ([]*models.Account, error) {
 	var accounts []*models.Account
-	result := r.db.WithContext(ctx).Limit(limit).Offset(offset).Find(&accounts)
+	result := paginate(r.db.WithContext(ctx), "created_at", "account_id", page).Find(&accounts)
 	if result.Error != nil {
 		return nil, result.Error
 	}
 	return accounts, nil
 }
 
-// Update updates an account in the database
-func (r *AccountRepository) Update(ctx context.Context, account *models.Account) error {
-	result := r.db.WithContext(ctx).Save(account)
-	return result.Error
+// Count counts all accounts
+func (r *AccountRepository) Count(ctx context.Context) (int64, error) {
+	var count int64
+	result := r.db.WithContext(ctx).Model(&models.Account{}).Count(&count)
+	if result.Error != nil {
+		return 0, result.Error
+	}
+	return count, nil
+}
+
+// EstimateCount returns a cheap estimate of the number of accounts
+func (r *AccountRepository) EstimateCount(ctx context.Context) (int64, error) {
+	return estimateRows(ctx, r.db, "accounts", &models.Account{})
 }
 
-// Delete soft deletes an account by ID
-func (r *AccountRepository) Delete(ctx context.Context, id string) error {
-	result := r.db.WithContext(ctx).Where("account_id = ?", id).Delete(&models.Account{})
+// CountByCustomerID counts the accounts of a specific customer
+func (r *AccountRepository) CountByCustomerID(ctx context.Context, customerID string) (int64, error) {
+	var count int64
+	result := r.db.WithContext(ctx).Model(&models.Account{}).Where("customer_id = ?", customerID).Count(&count)
 	if result.Error != nil {
-		return result.Error
+		return 0, result.Error
 	}
-	if result.RowsAffected == 0 {
-		return errors.New("account not found")
+	return count, nil
+}
+
+// Update applies update to the locked account and saves its account type,
+// status and update time, in one transaction. When change is not nil the
+// account then moves to change.ToStatus and the change is recorded in the
+// status history; FromStatus is filled in from the locked account. The
+// balance only changes through the ledger.
+func (r *AccountRepository) Update(ctx context.Context, accountID string, update AccountUpdate, change *models.AccountStatusChange) (*models.Account, error) {
+	var account models.Account
+	err := r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		result := tx.Clauses(clause.Locking{Strength: "UPDATE"}).
+			Where("account_id = ?", accountID).
+			First(&account)
+		if result.Error != nil {
+			if errors.Is(result.Error, gorm.ErrRecordNotFound) {
+				return notFound("account")
+			}
+			return result.Error
+		}
+
+		if err := update(&account); err != nil {
+			return err
+		}
+
+		account.UpdatedAt = time.Now()
+		if change != nil {
+			change.FromStatus = account.Status
+			account.Status = change.ToStatus
+			account.UpdatedAt = change.ChangedAt
+		}
+		result = tx.Model(&account).Select("account_type", "status", "updated_at").Updates(&account)
+		if result.Error != nil {
+			return translateError(result.Error)
+		}
+
+		if change == nil {
+			return nil
+		}
+		return translateError(tx.Create(change).Error)
+	})
+	if err != nil {
+		return nil, err
+	}
+	return &account, nil
+}
+
+// Delete soft deletes an account by ID. The guard runs against the locked
+// account first, so its status and balance cannot change in between.
+func (r *AccountRepository) Delete(ctx context.Context, id string, guard StatusGuard) error {
+	return r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		var account models.Account
+		result := tx.Clauses(clause.Locking{Strength: "UPDATE"}).
+			Where("account_id = ?", id).
+			First(&account)
+		if result.Error != nil {
+			if errors.Is(result.Error, gorm.ErrRecordNotFound) {
+				return notFound("account")
+			}
+			return result.Error
+		}
+
+		if err := guard(&account); err != nil {
+			return err
+		}
+
+		return translateError(tx.Delete(&account).Error)
+	})
+}
+
+// ChangeStatus moves an account to change.ToStatus and records the change
+// in the status history, in one transaction. The account row stays locked
+// until commit, so the guard sees a status and balance that cannot change
+// underneath it. FromStatus is filled in from the locked account.
+func (r *AccountRepository) ChangeStatus(ctx context.Context, change *models.AccountStatusChange, guard StatusGuard) (*models.Account, error) {
+	return r.Update(ctx, change.AccountID, AccountUpdate(guard), change)
+}
+
+// FindStatusHistory lists the status changes of an account, oldest first
+func (r *AccountRepository) FindStatusHistory(ctx context.Context, accountID string) ([]*models.AccountStatusChange, error) {
+	var changes []*models.AccountStatusChange
+	result := r.db.WithContext(ctx).Where("account_id = ?", accountID).Order("changed_at, change_id").Find(&changes)
+	if result.Error != nil {
+		return nil, result.Error
+	}
+	return changes, nil
+}
+
+// AccountSerialSequence is the database sequence account number serials are
+// taken from
+const AccountSerialSequence = "account_number_serial"
+
+// NextAccountSerial takes the next serial for a new account number. Values
+// taken by transactions that roll back are skipped, never reused.
+func (r *AccountRepository) NextAccountSerial(ctx context.Context) (int64, error) {
+	var serial int64
+	result := r.db.WithContext(ctx).Raw("SELECT nextval(?)", AccountSerialSequence).Scan(&serial)
+	if result.Error != nil {
+		return 0, result.Error
 	}
-	return nil
+	return serial, nil
 }
 
-// IsAccountNumberTaken checks if an account number is already taken
-func (r *AccountRepository) IsAccountNumberTaken(ctx context.Context, accountNumber string) (bool, error) {
+// CountByCustomerAndType counts the accounts of a customer with the given account type
+func (r *AccountRepository) CountByCustomerAndType(ctx context.Context, customerID, accountType string) (int64, error) {
 	var count int64
-	result := r.db.WithContext(ctx).Model(&models.Account{}).Where("account_number = ?", accountNumber).Count(&count)
+	result := r.db.WithContext(ctx).Model(&models.Account{}).Where("customer_id = ? AND account_type = ?", customerID, accountType).Count(&count)
 	if result.Error != nil {
-		return false, result.Error
+		return 0, result.Error
 	}
-	return count > 0, nil
+	return count, nil
 }
@@ -12,11 +12,11 @@ import (
 type ICustomerRepository interface {
 	Create(ctx context.Context, customer *models.Customer) error
 	FindByID(ctx context.Context, id string) (*models.Customer, error)
-	FindAll(ctx context.Context, limit, offset int) ([]*models.Customer, error)
+	FindAll(ctx context.Context, page PageQuery) ([]*models.Customer, error)
+	Count(ctx context.Context) (int64, error)
+	EstimateCount(ctx context.Context) (int64, error)
 	Update(ctx context.Context, customer *models.Customer) error
 	Delete(ctx context.Context, id string) error
-	IsEmailTaken(ctx context.Context, email string) (bool, error)
-	IsPhoneTaken(ctx context.Context, phone string) (bool, error)
 }
 
 // CustomerRepository implements ICustomerRepository interface
@@ -32,7 +32,7 @@ func NewCustomerRepository(db *gorm.DB) ICustomerRepository {
 // Create creates a new customer in the database
 func (r *CustomerRepository) Create(ctx context.Context, customer *models.Customer) error {
 	result := r.db.WithContext(ctx).Create(customer)
-	return result.Error
+	return translateError(result.Error)
 }
 
 // FindByID finds a customer by ID
@@ -41,27 +41,42 @@ func (r *CustomerRepository) FindByID(ctx context.Context, id string) (*models.C
 	result := r.db.WithContext(ctx).Where("customer_id = ?", id).First(&customer)
 	if result.Error != nil {
 		if errors.Is(result.Error, gorm.ErrRecordNotFound) {
-			return nil, errors.New("customer not found")
+			return nil, notFound("customer")
 		}
 		return nil, result.Error
 	}
 	return &customer, nil
 }
 
-// FindAll retrieves all customers with pagination
-func (r *CustomerRepository) FindAll(ctx context.Context, limit, offset int) ([]*models.Customer, error) {
+// FindAll retrieves one page of customers, oldest first
+func (r *CustomerRepository) FindAll(ctx context.Context, page PageQuery) ([]*models.Customer, error) {
 	var customers []*models.Customer
-	result := r.db.WithContext(ctx).Limit(limit).Offset(offset).Find(&customers)
+	result := paginate(r.db.WithContext(ctx), "created_at", "customer_id", page).Find(&customers)
 	if result.Error != nil {
 		return nil, result.Error
 	}
 	return customers, nil
 }
 
+// Count counts all customers
+func (r *CustomerRepository) Count(ctx context.Context) (int64, error) {
+	var count int64
+	result := r.db.WithContext(ctx).Model(&models.Customer{}).Count(&count)
+	if result.Error != nil {
+		return 0, result.Error
+	}
+	return count, nil
+}
+
+// EstimateCount returns a cheap estimate of the number of customers
+func (r *CustomerRepository) EstimateCount(ctx context.Context) (int64, error) {
+	return estimateRows(ctx, r.db, "customers", &models.Customer{})
+}
+
 // Update updates a customer in the database
 func (r *CustomerRepository) Update(ctx context.Context, customer *models.Customer) error {
 	result := r.db.WithContext(ctx).Save(customer)
-	return result.Error
+	return translateError(result.Error)
 }
 
 // Delete soft deletes a customer by ID
@@ -71,27 +86,7 @@ func (r *CustomerRepository) Delete(ctx context.Context, id string) error {
 		return result.Error
 	}
 	if result.RowsAffected == 0 {
-		return errors.New("customer not found")
+		return notFound("customer")
 	}
 	return nil
 }
-
-// IsEmailTaken checks if an email is already taken
-func (r *CustomerRepository) IsEmailTaken(ctx context.Context, email string) (bool, error) {
-	var count int64
-	result := r.db.WithContext(ctx).Model(&models.Customer{}).Where("email = ?", email).Count(&count)
-	if result.Error != nil {
-		return false, result.Error
-	}
-	return count > 0, nil
-}
-
-// IsPhoneTaken checks if a phone number is already taken
-func (r *CustomerRepository) IsPhoneTaken(ctx context.Context, phone string) (bool, error) {
-	var count int64
-	result := r.db.WithContext(ctx).Model(&models.Customer{}).Where("phone = ?", phone).Count(&count)
-	if result.Error != nil {
-		return false, result.Error
-	}
-	return count > 0, nil
-}
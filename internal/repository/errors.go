@@ -0,0 +1,67 @@
+package repository
+
+import (
+	"errors"
+	"fmt"
+)
+
+// Error kinds returned by the repositories; match them with errors.Is
+var (
+	// ErrNotFound is matched by every NotFoundError
+	ErrNotFound = errors.New("not found")
+	// ErrAlreadyExists is matched by every AlreadyExistsError
+	ErrAlreadyExists = errors.New("already exists")
+	// ErrConflict is matched by every ConflictError
+	ErrConflict = errors.New("conflict")
+)
+
+// NotFoundError reports that a record does not exist
+type NotFoundError struct {
+	Resource string // e.g. customer, account
+}
+
+func (e *NotFoundError) Error() string {
+	return e.Resource + " not found"
+}
+
+func (e *NotFoundError) Is(target error) bool {
+	return target == ErrNotFound
+}
+
+// AlreadyExistsError reports that a record would duplicate a unique field
+type AlreadyExistsError struct {
+	Resource string // e.g. customer, user
+	Field    string // e.g. email, phone_number; empty when the field is not known
+}
+
+func (e *AlreadyExistsError) Error() string {
+	if e.Field == "" {
+		return e.Resource + " already exists"
+	}
+	return fmt.Sprintf("%s %s is already in use", e.Resource, e.Field)
+}
+
+func (e *AlreadyExistsError) Is(target error) bool {
+	return target == ErrAlreadyExists
+}
+
+// ConflictError reports a request that the current state of the data does
+// not allow, such as a debit beyond the balance. Reason is a stable,
+// machine-readable code such as INSUFFICIENT_FUNDS.
+type ConflictError struct {
+	Reason  string
+	Message string
+}
+
+func (e *ConflictError) Error() string {
+	return e.Message
+}
+
+func (e *ConflictError) Is(target error) bool {
+	return target == ErrConflict
+}
+
+// notFound returns a NotFoundError for the resource
+func notFound(resource string) error {
+	return &NotFoundError{Resource: resource}
+}
@@ -0,0 +1,97 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/paudelanil/grpc-crud/models"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// IIdempotencyRepository defines the interface for idempotency record operations
+type IIdempotencyRepository interface {
+	Create(ctx context.Context, record *models.IdempotencyRecord) (bool, error)
+	FindByKey(ctx context.Context, userID, key string) (*models.IdempotencyRecord, error)
+	Complete(ctx context.Context, id string, response []byte, expiresAt time.Time) error
+	Fail(ctx context.Context, id string, failure []byte, expiresAt time.Time) error
+	Delete(ctx context.Context, id string) error
+	DeleteExpired(ctx context.Context, now time.Time) (int64, error)
+}
+
+// IdempotencyRepository implements IIdempotencyRepository interface
+type IdempotencyRepository struct {
+	db *gorm.DB
+}
+
+// NewIdempotencyRepository creates a new instance of IdempotencyRepository
+func NewIdempotencyRepository(db *gorm.DB) IIdempotencyRepository {
+	return &IdempotencyRepository{db: db}
+}
+
+// Create inserts a record unless one already exists for the same user and
+// key; it reports whether the record was inserted
+func (r *IdempotencyRepository) Create(ctx context.Context, record *models.IdempotencyRecord) (bool, error) {
+	result := r.db.WithContext(ctx).Clauses(clause.OnConflict{DoNothing: true}).Create(record)
+	if result.Error != nil {
+		return false, result.Error
+	}
+	return result.RowsAffected == 1, nil
+}
+
+// FindByKey finds a record by user and idempotency key
+func (r *IdempotencyRepository) FindByKey(ctx context.Context, userID, key string) (*models.IdempotencyRecord, error) {
+	var record models.IdempotencyRecord
+	result := r.db.WithContext(ctx).Where("user_id = ? AND idempotency_key = ?", userID, key).First(&record)
+	if result.Error != nil {
+		if errors.Is(result.Error, gorm.ErrRecordNotFound) {
+			return nil, notFound("idempotency record")
+		}
+		return nil, result.Error
+	}
+	return &record, nil
+}
+
+// Complete stores the serialized response of a finished request and how
+// long it is replayed
+func (r *IdempotencyRepository) Complete(ctx context.Context, id string, response []byte, expiresAt time.Time) error {
+	result := r.db.WithContext(ctx).
+		Model(&models.IdempotencyRecord{}).
+		Where("idempotency_id = ?", id).
+		Updates(map[string]interface{}{
+			"response":     response,
+			"completed_at": time.Now(),
+			"expires_at":   expiresAt,
+		})
+	return result.Error
+}
+
+// Fail stores the serialized error of a failed request and how long it is
+// replayed
+func (r *IdempotencyRepository) Fail(ctx context.Context, id string, failure []byte, expiresAt time.Time) error {
+	result := r.db.WithContext(ctx).
+		Model(&models.IdempotencyRecord{}).
+		Where("idempotency_id = ?", id).
+		Updates(map[string]interface{}{
+			"failure":      failure,
+			"completed_at": time.Now(),
+			"expires_at":   expiresAt,
+		})
+	return result.Error
+}
+
+// Delete removes a record so its key can be used again
+func (r *IdempotencyRepository) Delete(ctx context.Context, id string) error {
+	result := r.db.WithContext(ctx).Where("idempotency_id = ?", id).Delete(&models.IdempotencyRecord{})
+	return result.Error
+}
+
+// DeleteExpired removes all records that expired before now
+func (r *IdempotencyRepository) DeleteExpired(ctx context.Context, now time.Time) (int64, error) {
+	result := r.db.WithContext(ctx).Where("expires_at < ?", now).Delete(&models.IdempotencyRecord{})
+	if result.Error != nil {
+		return 0, result.Error
+	}
+	return result.RowsAffected, nil
+}
@@ -0,0 +1,201 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"sort"
+
+	"github.com/paudelanil/grpc-crud/models"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// ErrCurrencyMismatch is returned when a journal entry posts to an account
+// held in another currency
+var ErrCurrencyMismatch = &ConflictError{
+	Reason:  "CURRENCY_MISMATCH",
+	Message: "posting currency does not match account currency",
+}
+
+// AccountGuard inspects the locked accounts of a journal entry before it is
+// written; returning an error rolls the whole entry back
+type AccountGuard func(accounts map[string]*models.Account) error
+
+// ILedgerRepository defines the interface for ledger data operations
+type ILedgerRepository interface {
+	CreateEntry(ctx context.Context, entry *models.JournalEntry, guard AccountGuard) error
+	OpenAccount(ctx context.Context, account *models.Account, entry *models.JournalEntry) error
+	FindEntryByID(ctx context.Context, id string) (*models.JournalEntry, error)
+	FindPostingsByAccountID(ctx context.Context, accountID string, limit, offset int) ([]*models.Posting, error)
+	SumPostingsByAccountID(ctx context.Context, accountID string) (int64, error)
+	RebuildBalance(ctx context.Context, accountID string) (int64, error)
+}
+
+// LedgerRepository implements ILedgerRepository interface
+type LedgerRepository struct {
+	db *gorm.DB
+}
+
+// NewLedgerRepository creates a new instance of LedgerRepository
+func NewLedgerRepository(db *gorm.DB) ILedgerRepository {
+	return &LedgerRepository{db: db}
+}
+
+// CreateEntry stores a journal entry with its postings and applies the
+// postings to the cached account balances in a single transaction. The
+// affected account rows stay locked until commit, so the optional guard sees
+// balances that cannot change underneath it.
+func (r *LedgerRepository) CreateEntry(ctx context.Context, entry *models.JournalEntry, guard AccountGuard) error {
+	return r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		// Sum the postings per customer account; internal accounts have no cached balance
+		deltas := make(map[string]int64)
+		for _, posting := range entry.Postings {
+			if models.IsInternalAccount(posting.AccountID) {
+				continue
+			}
+			deltas[posting.AccountID] += posting.Amount
+		}
+
+		accountIDs := make([]string, 0, len(deltas))
+		for id := range deltas {
+			accountIDs = append(accountIDs, id)
+		}
+		// Lock in a stable order so concurrent entries cannot deadlock
+		sort.Strings(accountIDs)
+
+		locked := make(map[string]*models.Account, len(accountIDs))
+		if len(accountIDs) > 0 {
+			var accounts []*models.Account
+			result := tx.Clauses(clause.Locking{Strength: "UPDATE"}).
+				Where("account_id IN ?", accountIDs).
+				Order("account_id").
+				Find(&accounts)
+			if result.Error != nil {
+				return result.Error
+			}
+			if len(accounts) != len(accountIDs) {
+				return notFound("account")
+			}
+			for _, account := range accounts {
+				if account.Currency != entry.Currency {
+					return ErrCurrencyMismatch
+				}
+				locked[account.ID] = account
+			}
+		}
+
+		if guard != nil {
+			if err := guard(locked); err != nil {
+				return err
+			}
+		}
+
+		if err := tx.Create(entry).Error; err != nil {
+			return translateError(err)
+		}
+
+		for _, id := range accountIDs {
+			result := tx.Model(&models.Account{}).
+				Where("account_id = ?", id).
+				Update("balance_minor", gorm.Expr("balance_minor + ?", deltas[id]))
+			if result.Error != nil {
+				return result.Error
+			}
+		}
+		return nil
+	})
+}
+
+// OpenAccount creates an account together with the journal entry that
+// funds it, so an account is never left without its opening deposit. The
+// entry's postings to the new account become its starting balance.
+func (r *LedgerRepository) OpenAccount(ctx context.Context, account *models.Account, entry *models.JournalEntry) error {
+	return r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if entry.Currency != account.Currency {
+			return ErrCurrencyMismatch
+		}
+
+		account.Balance = 0
+		for _, posting := range entry.Postings {
+			if posting.AccountID == account.ID {
+				account.Balance += posting.Amount
+			}
+		}
+
+		if err := tx.Create(account).Error; err != nil {
+			return translateError(err)
+		}
+		return translateError(tx.Create(entry).Error)
+	})
+}
+
+// FindEntryByID finds a journal entry by ID together with its postings
+func (r *LedgerRepository) FindEntryByID(ctx context.Context, id string) (*models.JournalEntry, error) {
+	var entry models.JournalEntry
+	result := r.db.WithContext(ctx).Preload("Postings").Where("entry_id = ?", id).First(&entry)
+	if result.Error != nil {
+		if errors.Is(result.Error, gorm.ErrRecordNotFound) {
+			return nil, notFound("journal entry")
+		}
+		return nil, result.Error
+	}
+	return &entry, nil
+}
+
+// FindPostingsByAccountID retrieves the postings of an account, oldest first, with pagination
+func (r *LedgerRepository) FindPostingsByAccountID(ctx context.Context, accountID string, limit, offset int) ([]*models.Posting, error) {
+	var postings []*models.Posting
+	result := r.db.WithContext(ctx).
+		Where("account_id = ?", accountID).
+		Order("created_at, posting_id").
+		Limit(limit).
+		Offset(offset).
+		Find(&postings)
+	if result.Error != nil {
+		return nil, result.Error
+	}
+	return postings, nil
+}
+
+// SumPostingsByAccountID returns the balance of an account as the sum of its postings
+func (r *LedgerRepository) SumPostingsByAccountID(ctx context.Context, accountID string) (int64, error) {
+	var sum int64
+	result := r.db.WithContext(ctx).
+		Model(&models.Posting{}).
+		Where("account_id = ?", accountID).
+		Select("COALESCE(SUM(amount_minor), 0)").
+		Scan(&sum)
+	if result.Error != nil {
+		return 0, result.Error
+	}
+	return sum, nil
+}
+
+// RebuildBalance recomputes the cached balance of an account from its postings
+func (r *LedgerRepository) RebuildBalance(ctx context.Context, accountID string) (int64, error) {
+	var balance int64
+	err := r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		var account models.Account
+		result := tx.Clauses(clause.Locking{Strength: "UPDATE"}).Where("account_id = ?", accountID).First(&account)
+		if result.Error != nil {
+			if errors.Is(result.Error, gorm.ErrRecordNotFound) {
+				return notFound("account")
+			}
+			return result.Error
+		}
+
+		result = tx.Model(&models.Posting{}).
+			Where("account_id = ?", accountID).
+			Select("COALESCE(SUM(amount_minor), 0)").
+			Scan(&balance)
+		if result.Error != nil {
+			return result.Error
+		}
+
+		return tx.Model(&account).Update("balance_minor", balance).Error
+	})
+	if err != nil {
+		return 0, err
+	}
+	return balance, nil
+}
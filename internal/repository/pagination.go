@@ -0,0 +1,53 @@
+package repository
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+// Cursor is the position of a row in (created_at, id) order
+type Cursor struct {
+	CreatedAt time.Time
+	ID        string
+}
+
+// PageQuery selects one page of a list in (created_at, id) order
+type PageQuery struct {
+	Limit  int
+	Offset int     // legacy page_number paging; ignored when After is set
+	After  *Cursor // keyset paging: rows after this position
+}
+
+// paginate orders a query by creation time and primary key and applies the
+// page. key is the qualified primary key column.
+func paginate(query *gorm.DB, createdAt, key string, page PageQuery) *gorm.DB {
+	if page.After != nil {
+		query = query.Where(fmt.Sprintf("(%s, %s) > (?, ?)", createdAt, key), page.After.CreatedAt, page.After.ID)
+	} else if page.Offset > 0 {
+		query = query.Offset(page.Offset)
+	}
+	return query.Order(createdAt + ", " + key).Limit(page.Limit)
+}
+
+// estimateRows returns the planner's estimate of the rows in a table,
+// falling back to an exact count of model when the table has not been
+// analyzed yet. Soft-deleted rows are included in estimates.
+func estimateRows(ctx context.Context, db *gorm.DB, table string, model interface{}) (int64, error) {
+	var estimate float64
+	result := db.WithContext(ctx).Raw("SELECT reltuples FROM pg_class WHERE oid = to_regclass(?)", table).Scan(&estimate)
+	if result.Error != nil {
+		return 0, result.Error
+	}
+	if estimate >= 0 && result.RowsAffected > 0 {
+		return int64(estimate), nil
+	}
+
+	var count int64
+	if err := db.WithContext(ctx).Model(model).Count(&count).Error; err != nil {
+		return 0, err
+	}
+	return count, nil
+}
@@ -0,0 +1,64 @@
+package repository
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/jackc/pgx/v5/pgconn"
+)
+
+// Postgres SQLSTATE codes of the integrity violations that are translated.
+// Foreign keys are not created by the migration, so their violations
+// cannot occur.
+const (
+	pgUniqueViolation = "23505"
+	pgCheckViolation  = "23514"
+)
+
+// constraintFields maps unique constraints to the request field they
+// guard. Constraints missing here are reported without a field, so index
+// names never reach clients.
+var constraintFields = map[string]string{
+	"idx_customers_email":         "email",
+	"idx_customers_phone":         "phone_number",
+	"idx_accounts_account_number": "account_number",
+	"idx_users_username":          "username",
+	"idx_users_email":             "email",
+	"idx_idempotency_scope_key":   "idempotency_key",
+}
+
+// translateError turns Postgres integrity violations into domain errors,
+// naming the offending field where it is known. Other errors are returned
+// unchanged.
+func translateError(err error) error {
+	var pgErr *pgconn.PgError
+	if !errors.As(err, &pgErr) {
+		return err
+	}
+
+	resource := resourceName(pgErr.TableName)
+
+	switch pgErr.Code {
+	case pgUniqueViolation:
+		return &AlreadyExistsError{
+			Resource: resource,
+			Field:    constraintFields[pgErr.ConstraintName],
+		}
+	case pgCheckViolation:
+		return &ConflictError{
+			Reason:  "CHECK_VIOLATION",
+			Message: fmt.Sprintf("%s has a value that is not allowed", resource),
+		}
+	}
+	return err
+}
+
+// resourceName returns the singular resource name of a table, e.g.
+// customers -> customer
+func resourceName(table string) string {
+	if table == "" {
+		return "record"
+	}
+	return strings.TrimSuffix(table, "s")
+}
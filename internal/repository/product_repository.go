@@ -0,0 +1,84 @@
+package repository
+
+import (
+	"context"
+	"errors"
+
+	"github.com/paudelanil/grpc-crud/models"
+	"gorm.io/gorm"
+)
+
+// IProductRepository defines the interface for account product catalog operations
+type IProductRepository interface {
+	Create(ctx context.Context, product *models.AccountProduct) error
+	FindByCode(ctx context.Context, code string) (*models.AccountProduct, error)
+	FindAll(ctx context.Context, includeInactive bool) ([]*models.AccountProduct, error)
+	Update(ctx context.Context, product *models.AccountProduct) error
+}
+
+// ProductRepository implements IProductRepository interface
+type ProductRepository struct {
+	db *gorm.DB
+}
+
+// NewProductRepository creates a new instance of ProductRepository
+func NewProductRepository(db *gorm.DB) IProductRepository {
+	return &ProductRepository{db: db}
+}
+
+// Create creates a new product together with its currencies
+func (r *ProductRepository) Create(ctx context.Context, product *models.AccountProduct) error {
+	result := r.db.WithContext(ctx).Create(product)
+	return translateError(result.Error)
+}
+
+// FindByCode finds a product by code together with its currencies
+func (r *ProductRepository) FindByCode(ctx context.Context, code string) (*models.AccountProduct, error) {
+	var product models.AccountProduct
+	result := r.db.WithContext(ctx).Preload("Currencies").Where("code = ?", code).First(&product)
+	if result.Error != nil {
+		if errors.Is(result.Error, gorm.ErrRecordNotFound) {
+			return nil, notFound("product")
+		}
+		return nil, result.Error
+	}
+	return &product, nil
+}
+
+// FindAll retrieves the products ordered by code, optionally including inactive ones
+func (r *ProductRepository) FindAll(ctx context.Context, includeInactive bool) ([]*models.AccountProduct, error) {
+	var products []*models.AccountProduct
+	query := r.db.WithContext(ctx).Preload("Currencies").Order("code")
+	if !includeInactive {
+		query = query.Where("active = ?", true)
+	}
+	result := query.Find(&products)
+	if result.Error != nil {
+		return nil, result.Error
+	}
+	return products, nil
+}
+
+// Update replaces the settings and currencies of an existing product
+func (r *ProductRepository) Update(ctx context.Context, product *models.AccountProduct) error {
+	return r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		result := tx.Model(&models.AccountProduct{}).
+			Where("code = ?", product.Code).
+			Select("name", "description", "allow_withdrawals", "max_accounts_per_customer", "active", "number_prefix", "updated_at").
+			Updates(product)
+		if result.Error != nil {
+			return translateError(result.Error)
+		}
+		if result.RowsAffected == 0 {
+			return notFound("product")
+		}
+
+		if err := tx.Where("product_code = ?", product.Code).Delete(&models.ProductCurrency{}).Error; err != nil {
+			return err
+		}
+		if len(product.Currencies) == 0 {
+			return nil
+		}
+		return translateError(tx.Create(&product.Currencies).Error)
+	})
+}
@@ -0,0 +1,137 @@
+// Package repositorytest provides in-memory repositories for tests
+package repositorytest
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"github.com/paudelanil/grpc-crud/internal/repository"
+	"github.com/paudelanil/grpc-crud/models"
+)
+
+var (
+	_ repository.ISessionRepository = (*SessionRepository)(nil)
+	_ repository.IUserRepository    = (*UserRepository)(nil)
+)
+
+// SessionRepository is an in-memory repository.ISessionRepository
+type SessionRepository struct {
+	mu       sync.Mutex
+	sessions map[string]*models.Session
+	revoked  map[string]bool
+}
+
+// NewSessionRepository creates an empty SessionRepository
+func NewSessionRepository() *SessionRepository {
+	return &SessionRepository{sessions: make(map[string]*models.Session), revoked: make(map[string]bool)}
+}
+
+func (r *SessionRepository) Create(ctx context.Context, session *models.Session) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	copied := *session
+	r.sessions[session.ID] = &copied
+	return nil
+}
+
+func (r *SessionRepository) FindByID(ctx context.Context, id string) (*models.Session, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	session, ok := r.sessions[id]
+	if !ok {
+		return nil, &repository.NotFoundError{Resource: "session"}
+	}
+	copied := *session
+	return &copied, nil
+}
+
+func (r *SessionRepository) RotateRefreshToken(ctx context.Context, id, currentTokenID, nextTokenID string, expiresAt time.Time) (bool, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	session, ok := r.sessions[id]
+	if !ok || session.RevokedAt != nil || session.RefreshTokenID != currentTokenID {
+		return false, nil
+	}
+	session.RefreshTokenID = nextTokenID
+	session.ExpiresAt = expiresAt
+	return true, nil
+}
+
+func (r *SessionRepository) Revoke(ctx context.Context, id, reason string) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if session, ok := r.sessions[id]; ok {
+		now := time.Now()
+		session.RevokedAt = &now
+		session.RevokedReason = reason
+	}
+	return nil
+}
+
+func (r *SessionRepository) RevokeToken(ctx context.Context, token *models.RevokedToken) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.revoked[token.TokenID] = true
+	return nil
+}
+
+func (r *SessionRepository) IsTokenRevoked(ctx context.Context, tokenID string) (bool, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return r.revoked[tokenID], nil
+}
+
+func (r *SessionRepository) DeleteExpired(ctx context.Context, now time.Time) error {
+	return nil
+}
+
+// UserRepository is an in-memory repository.IUserRepository
+type UserRepository struct {
+	users []*models.User
+}
+
+// NewUserRepository creates a UserRepository holding users
+func NewUserRepository(users ...*models.User) *UserRepository {
+	return &UserRepository{users: users}
+}
+
+func (r *UserRepository) Create(ctx context.Context, user *models.User) error {
+	r.users = append(r.users, user)
+	return nil
+}
+
+func (r *UserRepository) FindByUsername(ctx context.Context, username string) (*models.User, error) {
+	for _, u := range r.users {
+		if u.Username == username {
+			return u, nil
+		}
+	}
+	return nil, &repository.NotFoundError{Resource: "user"}
+}
+
+func (r *UserRepository) FindByEmail(ctx context.Context, email string) (*models.User, error) {
+	for _, u := range r.users {
+		if u.Email == email {
+			return u, nil
+		}
+	}
+	return nil, &repository.NotFoundError{Resource: "user"}
+}
+
+func (r *UserRepository) FindByID(ctx context.Context, id string) (*models.User, error) {
+	for _, u := range r.users {
+		if u.ID == id {
+			return u, nil
+		}
+	}
+	return nil, &repository.NotFoundError{Resource: "user"}
+}
+
+func (r *UserRepository) Update(ctx context.Context, user *models.User) error {
+	return nil
+}
+
+func (r *UserRepository) Delete(ctx context.Context, id string) error {
+	return nil
+}
@@ -0,0 +1,109 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/paudelanil/grpc-crud/models"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// ISessionRepository defines the interface for session and token revocation operations
+type ISessionRepository interface {
+	Create(ctx context.Context, session *models.Session) error
+	FindByID(ctx context.Context, id string) (*models.Session, error)
+	RotateRefreshToken(ctx context.Context, id, currentTokenID, nextTokenID string, expiresAt time.Time) (bool, error)
+	Revoke(ctx context.Context, id, reason string) error
+	RevokeToken(ctx context.Context, token *models.RevokedToken) error
+	IsTokenRevoked(ctx context.Context, tokenID string) (bool, error)
+	DeleteExpired(ctx context.Context, now time.Time) error
+}
+
+// SessionRepository implements ISessionRepository interface
+type SessionRepository struct {
+	db *gorm.DB
+}
+
+// NewSessionRepository creates a new instance of SessionRepository
+func NewSessionRepository(db *gorm.DB) ISessionRepository {
+	return &SessionRepository{db: db}
+}
+
+// Create creates a new session in the database
+func (r *SessionRepository) Create(ctx context.Context, session *models.Session) error {
+	result := r.db.WithContext(ctx).Create(session)
+	return result.Error
+}
+
+// FindByID finds a session by ID
+func (r *SessionRepository) FindByID(ctx context.Context, id string) (*models.Session, error) {
+	var session models.Session
+	result := r.db.WithContext(ctx).Where("session_id = ?", id).First(&session)
+	if result.Error != nil {
+		if errors.Is(result.Error, gorm.ErrRecordNotFound) {
+			return nil, notFound("session")
+		}
+		return nil, result.Error
+	}
+	return &session, nil
+}
+
+// RotateRefreshToken replaces the current refresh token of an active session.
+// It reports false when currentTokenID is no longer the session's refresh
+// token, which means the token was already used.
+func (r *SessionRepository) RotateRefreshToken(ctx context.Context, id, currentTokenID, nextTokenID string, expiresAt time.Time) (bool, error) {
+	result := r.db.WithContext(ctx).
+		Model(&models.Session{}).
+		Where("session_id = ? AND refresh_token_id = ? AND revoked_at IS NULL", id, currentTokenID).
+		Updates(map[string]interface{}{
+			"refresh_token_id": nextTokenID,
+			"expires_at":       expiresAt,
+			"updated_at":       time.Now(),
+		})
+	if result.Error != nil {
+		return false, result.Error
+	}
+	return result.RowsAffected == 1, nil
+}
+
+// Revoke ends a session so none of its tokens are accepted any more
+func (r *SessionRepository) Revoke(ctx context.Context, id, reason string) error {
+	now := time.Now()
+	result := r.db.WithContext(ctx).
+		Model(&models.Session{}).
+		Where("session_id = ? AND revoked_at IS NULL", id).
+		Updates(map[string]interface{}{
+			"revoked_at":     now,
+			"revoked_reason": reason,
+			"updated_at":     now,
+		})
+	return result.Error
+}
+
+// RevokeToken records a single revoked token
+func (r *SessionRepository) RevokeToken(ctx context.Context, token *models.RevokedToken) error {
+	result := r.db.WithContext(ctx).Clauses(clause.OnConflict{DoNothing: true}).Create(token)
+	return result.Error
+}
+
+// IsTokenRevoked checks if a token ID has been revoked
+func (r *SessionRepository) IsTokenRevoked(ctx context.Context, tokenID string) (bool, error) {
+	var count int64
+	result := r.db.WithContext(ctx).Model(&models.RevokedToken{}).Where("token_id = ?", tokenID).Count(&count)
+	if result.Error != nil {
+		return false, result.Error
+	}
+	return count > 0, nil
+}
+
+// DeleteExpired removes sessions and revoked tokens that can no longer be presented
+func (r *SessionRepository) DeleteExpired(ctx context.Context, now time.Time) error {
+	return r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if err := tx.Where("expires_at < ?", now).Delete(&models.RevokedToken{}).Error; err != nil {
+			return err
+		}
+		return tx.Where("expires_at < ?", now).Delete(&models.Session{}).Error
+	})
+}
@@ -0,0 +1,109 @@
+package repository
+
+import (
+	"context"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+// StatementLine is one posting to an account with the details of its
+// journal entry
+type StatementLine struct {
+	PostingID   string
+	EntryID     string
+	EntryType   string
+	Reference   string
+	Description string
+	PostedAt    time.Time
+	Amount      int64 `gorm:"column:amount_minor"` // positive for credits, negative for debits
+}
+
+// StatementCursor marks the last line already read, so the next read
+// continues after it
+type StatementCursor struct {
+	PostedAt  time.Time
+	PostingID string
+}
+
+// StatementTotals sums the lines of a statement period
+type StatementTotals struct {
+	Credits int64 // sum of positive amounts
+	Debits  int64 // sum of negative amounts, as a positive number
+	Count   int64
+}
+
+// IStatementRepository defines the interface for reading account statements
+type IStatementRepository interface {
+	FindLines(ctx context.Context, accountID string, from, to time.Time, after *StatementCursor, limit int) ([]*StatementLine, error)
+	BalanceBefore(ctx context.Context, accountID string, from time.Time, after *StatementCursor) (int64, error)
+	Totals(ctx context.Context, accountID string, from, to time.Time) (*StatementTotals, error)
+}
+
+// StatementRepository implements IStatementRepository interface
+type StatementRepository struct {
+	db *gorm.DB
+}
+
+// NewStatementRepository creates a new instance of StatementRepository
+func NewStatementRepository(db *gorm.DB) IStatementRepository {
+	return &StatementRepository{db: db}
+}
+
+// lines selects the postings of an account joined with their entries
+func (r *StatementRepository) lines(ctx context.Context, accountID string) *gorm.DB {
+	return r.db.WithContext(ctx).
+		Table("postings AS p").
+		Joins("JOIN journal_entries AS e ON e.entry_id = p.entry_id").
+		Where("p.account_id = ?", accountID)
+}
+
+// FindLines retrieves the lines posted in [from, to) in posting order,
+// starting after the cursor when one is given
+func (r *StatementRepository) FindLines(ctx context.Context, accountID string, from, to time.Time, after *StatementCursor, limit int) ([]*StatementLine, error) {
+	query := r.lines(ctx, accountID).
+		Select("p.posting_id, p.entry_id, e.entry_type, e.reference, e.description, e.posted_at, p.amount_minor").
+		Where("e.posted_at >= ? AND e.posted_at < ?", from, to)
+	if after != nil {
+		query = query.Where("(e.posted_at, p.posting_id) > (?, ?)", after.PostedAt, after.PostingID)
+	}
+
+	var lines []*StatementLine
+	result := query.Order("e.posted_at, p.posting_id").Limit(limit).Scan(&lines)
+	if result.Error != nil {
+		return nil, result.Error
+	}
+	return lines, nil
+}
+
+// BalanceBefore returns the balance of an account before from, or, when a
+// cursor is given, right after the line it marks
+func (r *StatementRepository) BalanceBefore(ctx context.Context, accountID string, from time.Time, after *StatementCursor) (int64, error) {
+	query := r.lines(ctx, accountID).Select("COALESCE(SUM(p.amount_minor), 0)")
+	if after != nil {
+		query = query.Where("(e.posted_at, p.posting_id) <= (?, ?)", after.PostedAt, after.PostingID)
+	} else {
+		query = query.Where("e.posted_at < ?", from)
+	}
+
+	var balance int64
+	if result := query.Scan(&balance); result.Error != nil {
+		return 0, result.Error
+	}
+	return balance, nil
+}
+
+// Totals sums the credits and debits posted in [from, to)
+func (r *StatementRepository) Totals(ctx context.Context, accountID string, from, to time.Time) (*StatementTotals, error) {
+	var totals StatementTotals
+	result := r.lines(ctx, accountID).
+		Select(`COALESCE(SUM(CASE WHEN p.amount_minor > 0 THEN p.amount_minor ELSE 0 END), 0) AS credits,
+			COALESCE(SUM(CASE WHEN p.amount_minor < 0 THEN -p.amount_minor ELSE 0 END), 0) AS debits,
+			COUNT(*) AS count`).
+		Where("e.posted_at >= ? AND e.posted_at < ?", from, to).
+		Scan(&totals)
+	if result.Error != nil {
+		return nil, result.Error
+	}
+	return &totals, nil
+}
@@ -16,8 +16,6 @@ type IUserRepository interface {
 	FindByID(ctx context.Context, id string) (*models.User, error)
 	Update(ctx context.Context, user *models.User) error
 	Delete(ctx context.Context, id string) error
-	IsUsernameTaken(ctx context.Context, username string) (bool, error)
-	IsEmailTaken(ctx context.Context, email string) (bool, error)
 }
 
 // UserRepository implements IUserRepository interface
@@ -33,7 +31,7 @@ func NewUserRepository(db *gorm.DB) IUserRepository {
 // Create creates a new user in the database
 func (r *UserRepository) Create(ctx context.Context, user *models.User) error {
 	result := r.db.WithContext(ctx).Create(user)
-	return result.Error
+	return translateError(result.Error)
 }
 
 // FindByUsername finds a user by username
@@ -42,7 +40,7 @@ func (r *UserRepository) FindByUsername(ctx context.Context, username string) (*
 	result := r.db.WithContext(ctx).Where("username = ?", username).First(&user)
 	if result.Error != nil {
 		if errors.Is(result.Error, gorm.ErrRecordNotFound) {
-			return nil, errors.New("user not found")
+			return nil, notFound("user")
 		}
 		return nil, result.Error
 	}
@@ -55,7 +53,7 @@ func (r *UserRepository) FindByEmail(ctx context.Context, email string) (*models
 	result := r.db.WithContext(ctx).Where("email = ?", email).First(&user)
 	if result.Error != nil {
 		if errors.Is(result.Error, gorm.ErrRecordNotFound) {
-			return nil, errors.New("user not found")
+			return nil, notFound("user")
 		}
 		return nil, result.Error
 	}
@@ -68,7 +66,7 @@ func (r *UserRepository) FindByID(ctx context.Context, id string) (*models.User,
 	result := r.db.WithContext(ctx).Where("user_id = ?", id).First(&user)
 	if result.Error != nil {
 		if errors.Is(result.Error, gorm.ErrRecordNotFound) {
-			return nil, errors.New("user not found")
+			return nil, notFound("user")
 		}
 		return nil, result.Error
 	}
@@ -78,7 +76,7 @@ func (r *UserRepository) FindByID(ctx context.Context, id string) (*models.User,
 // Update updates a user in the database
 func (r *UserRepository) Update(ctx context.Context, user *models.User) error {
 	result := r.db.WithContext(ctx).Save(user)
-	return result.Error
+	return translateError(result.Error)
 }
 
 // Delete soft deletes a user by ID
@@ -88,27 +86,7 @@ func (r *UserRepository) Delete(ctx context.Context, id string) error {
 		return result.Error
 	}
 	if result.RowsAffected == 0 {
-		return errors.New("user not found")
+		return notFound("user")
 	}
 	return nil
 }
-
-// IsUsernameTaken checks if a username is already taken
-func (r *UserRepository) IsUsernameTaken(ctx context.Context, username string) (bool, error) {
-	var count int64
-	result := r.db.WithContext(ctx).Model(&models.User{}).Where("username = ?", username).Count(&count)
-	if result.Error != nil {
-		return false, result.Error
-	}
-	return count > 0, nil
-}
-
-// IsEmailTaken checks if an email is already taken
-func (r *UserRepository) IsEmailTaken(ctx context.Context, email string) (bool, error) {
-	var count int64
-	result := r.db.WithContext(ctx).Model(&models.User{}).Where("email = ?", email).Count(&count)
-	if result.Error != nil {
-		return false, result.Error
-	}
-	return count > 0, nil
-}
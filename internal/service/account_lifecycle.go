@@ -0,0 +1,193 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/paudelanil/grpc-crud/internal/repository"
+	"github.com/paudelanil/grpc-crud/models"
+	"github.com/paudelanil/grpc-crud/pb"
+)
+
+var (
+	// ErrBalanceNotZero is returned when an account with money in it is closed or deleted
+	ErrBalanceNotZero = &repository.ConflictError{Reason: "BALANCE_NOT_ZERO", Message: "account balance must be zero before it is closed"}
+	// ErrAccountNotClosed is returned when an account that is still open is deleted
+	ErrAccountNotClosed = &repository.ConflictError{Reason: "ACCOUNT_NOT_CLOSED", Message: "account must be closed before it is deleted"}
+)
+
+// FreezeAccount blocks money leaving an account
+func (s *AccountServiceImpl) FreezeAccount(ctx context.Context, actorID string, req *pb.FreezeAccountRequest) (*pb.FreezeAccountResponse, error) {
+	ctx, span := tracer.Start(ctx, "AccountService.FreezeAccount")
+	defer span.End()
+
+	account, err := s.changeStatus(ctx, actorID, req.AccountId, models.AccountStatusFrozen, req.Reason, req.Note, "reason")
+	if err != nil {
+		return nil, err
+	}
+
+	return &pb.FreezeAccountResponse{
+		Message: "Account frozen successfully",
+		Account: toAccountResponse(account),
+	}, nil
+}
+
+// UnfreezeAccount returns a frozen account to active
+func (s *AccountServiceImpl) UnfreezeAccount(ctx context.Context, actorID string, req *pb.UnfreezeAccountRequest) (*pb.UnfreezeAccountResponse, error) {
+	ctx, span := tracer.Start(ctx, "AccountService.UnfreezeAccount")
+	defer span.End()
+
+	account, err := s.changeStatus(ctx, actorID, req.AccountId, models.AccountStatusActive, req.Reason, req.Note, "reason",
+		func(account *models.Account) error {
+			if account.Status != models.AccountStatusFrozen {
+				return &repository.ConflictError{Reason: "ACCOUNT_NOT_FROZEN", Message: "account is not frozen"}
+			}
+			return nil
+		})
+	if err != nil {
+		return nil, err
+	}
+
+	return &pb.UnfreezeAccountResponse{
+		Message: "Account unfrozen successfully",
+		Account: toAccountResponse(account),
+	}, nil
+}
+
+// CloseAccount closes an account whose balance is zero
+func (s *AccountServiceImpl) CloseAccount(ctx context.Context, actorID string, req *pb.CloseAccountRequest) (*pb.CloseAccountResponse, error) {
+	ctx, span := tracer.Start(ctx, "AccountService.CloseAccount")
+	defer span.End()
+
+	account, err := s.changeStatus(ctx, actorID, req.AccountId, models.AccountStatusClosed, req.Reason, req.Note, "reason")
+	if err != nil {
+		return nil, err
+	}
+
+	return &pb.CloseAccountResponse{
+		Message: "Account closed successfully",
+		Account: toAccountResponse(account),
+	}, nil
+}
+
+// GetAccountStatusHistory lists the status changes of an account
+func (s *AccountServiceImpl) GetAccountStatusHistory(ctx context.Context, req *pb.GetAccountStatusHistoryRequest) (*pb.GetAccountStatusHistoryResponse, error) {
+	ctx, span := tracer.Start(ctx, "AccountService.GetAccountStatusHistory")
+	defer span.End()
+
+	if req.AccountId == "" {
+		return nil, invalidField("account_id", "account ID is required")
+	}
+
+	// Distinguish a missing account from one that never changed status
+	if _, err := s.accountRepo.FindByID(ctx, req.AccountId); err != nil {
+		return nil, err
+	}
+
+	changes, err := s.accountRepo.FindStatusHistory(ctx, req.AccountId)
+	if err != nil {
+		return nil, fmt.Errorf("failed to retrieve status history: %w", err)
+	}
+
+	response := &pb.GetAccountStatusHistoryResponse{}
+	for _, change := range changes {
+		response.Changes = append(response.Changes, &pb.AccountStatusChange{
+			FromStatus: change.FromStatus,
+			ToStatus:   change.ToStatus,
+			Reason:     change.Reason,
+			Note:       change.Note,
+			ChangedBy:  change.ChangedBy,
+			ChangedAt:  change.ChangedAt.Format(time.RFC3339),
+		})
+	}
+	return response, nil
+}
+
+// changeStatus moves an account to a new status if the state machine allows
+// it, recording who made the change and why. Closing also requires a zero
+// balance. Extra checks run against the locked account before the
+// transition rules. reasonField names the request field holding the reason.
+func (s *AccountServiceImpl) changeStatus(ctx context.Context, actorID, accountID, to, reason, note, reasonField string, checks ...repository.StatusGuard) (*models.Account, error) {
+	if accountID == "" {
+		return nil, invalidField("account_id", "account ID is required")
+	}
+
+	change, err := newStatusChange(actorID, accountID, to, reason, note, reasonField)
+	if err != nil {
+		return nil, err
+	}
+
+	guard := func(account *models.Account) error {
+		for _, check := range checks {
+			if err := check(account); err != nil {
+				return err
+			}
+		}
+		return checkTransition(account, to)
+	}
+
+	account, err := s.accountRepo.ChangeStatus(ctx, change, guard)
+	if err != nil {
+		return nil, err
+	}
+	return account, nil
+}
+
+// newStatusChange checks the reason for a status change and returns the
+// history entry recording it
+func newStatusChange(actorID, accountID, to, reason, note, reasonField string) (*models.AccountStatusChange, error) {
+	if reason == "" {
+		return nil, invalidField(reasonField, "a reason is required to change the account status")
+	}
+	if !slices.Contains(models.StatusReasons[to], reason) {
+		return nil, invalidField(reasonField, fmt.Sprintf("reason must be one of %s when the account becomes %s",
+			strings.Join(models.StatusReasons[to], ", "), to))
+	}
+
+	return &models.AccountStatusChange{
+		ID:        uuid.New().String(),
+		AccountID: accountID,
+		ToStatus:  to,
+		Reason:    reason,
+		Note:      note,
+		ChangedBy: actorID,
+		ChangedAt: time.Now(),
+	}, nil
+}
+
+// checkTransition checks that the locked account may move to status to
+func checkTransition(account *models.Account, to string) error {
+	if !canTransition(account.Status, to) {
+		return &repository.ConflictError{
+			Reason:  "INVALID_STATUS_TRANSITION",
+			Message: fmt.Sprintf("a %s account cannot become %s", account.Status, to),
+		}
+	}
+	if to == models.AccountStatusClosed && account.Balance != 0 {
+		return ErrBalanceNotZero
+	}
+	return nil
+}
+
+// canTransition reports whether the state machine allows from -> to
+func canTransition(from, to string) bool {
+	return slices.Contains(models.AccountTransitions[from], to)
+}
+
+// acceptsCredits reports whether money may be paid into the account
+func acceptsCredits(account *models.Account) bool {
+	switch account.Status {
+	case models.AccountStatusActive, models.AccountStatusDormant, models.AccountStatusFrozen:
+		return true
+	}
+	return false
+}
+
+// acceptsDebits reports whether money may be taken out of the account
+func acceptsDebits(account *models.Account) bool {
+	return account.Status == models.AccountStatusActive
+}
@@ -4,9 +4,13 @@ import (
 	"context"
 	"errors"
 	"fmt"
+	"strings"
 	"time"
 
 	"github.com/google/uuid"
+	"github.com/paudelanil/grpc-crud/internal/accountnumber"
+	"github.com/paudelanil/grpc-crud/internal/money"
+	"github.com/paudelanil/grpc-crud/internal/pagetoken"
 	"github.com/paudelanil/grpc-crud/internal/repository"
 	"github.com/paudelanil/grpc-crud/models"
 	"github.com/paudelanil/grpc-crud/pb"
@@ -16,94 +20,209 @@ import (
 type IAccountService interface {
 	CreateAccount(ctx context.Context, req *pb.CreateAccountRequest) (*pb.CreateAccountResponse, error)
 	GetAccount(ctx context.Context, req *pb.GetAccountRequest) (*pb.GetAccountResponse, error)
-	UpdateAccount(ctx context.Context, req *pb.UpdateAccountRequest) (*pb.UpdateAccountResponse, error)
+	UpdateAccount(ctx context.Context, actorID string, req *pb.UpdateAccountRequest) (*pb.UpdateAccountResponse, error)
 	DeleteAccount(ctx context.Context, req *pb.DeleteAccountRequest) (*pb.DeleteAccountResponse, error)
 	ListAccounts(ctx context.Context, req *pb.ListAccountRequest) (*pb.ListAccountResponse, error)
+	ListCustomerAccounts(ctx context.Context, customerID string, req *pb.ListAccountRequest) (*pb.ListAccountResponse, error)
+	Deposit(ctx context.Context, req *pb.DepositRequest) (*pb.DepositResponse, error)
+	Withdraw(ctx context.Context, req *pb.WithdrawRequest) (*pb.WithdrawResponse, error)
+	Transfer(ctx context.Context, req *pb.TransferRequest) (*pb.TransferResponse, error)
+	FreezeAccount(ctx context.Context, actorID string, req *pb.FreezeAccountRequest) (*pb.FreezeAccountResponse, error)
+	UnfreezeAccount(ctx context.Context, actorID string, req *pb.UnfreezeAccountRequest) (*pb.UnfreezeAccountResponse, error)
+	CloseAccount(ctx context.Context, actorID string, req *pb.CloseAccountRequest) (*pb.CloseAccountResponse, error)
+	GetAccountStatusHistory(ctx context.Context, req *pb.GetAccountStatusHistoryRequest) (*pb.GetAccountStatusHistoryResponse, error)
 }
 
+var (
+	// ErrInsufficientFunds is returned when a debit would take a balance below zero
+	ErrInsufficientFunds = &repository.ConflictError{Reason: "INSUFFICIENT_FUNDS", Message: "insufficient funds"}
+	// ErrAccountNotTransactable is returned when a frozen or closed account is used for money movement
+	ErrAccountNotTransactable = &repository.ConflictError{Reason: "ACCOUNT_NOT_TRANSACTABLE", Message: "account status does not allow this transaction"}
+	// ErrCurrencyMismatch is returned when money would move between accounts in different currencies
+	ErrCurrencyMismatch = repository.ErrCurrencyMismatch
+	// ErrProductInactive is returned when an account is opened with a withdrawn product
+	ErrProductInactive = &repository.ConflictError{Reason: "PRODUCT_INACTIVE", Message: "account product is no longer offered"}
+)
+
+// defaultCurrency is used when CreateAccount names no currency
+const defaultCurrency = "NPR"
+
 // AccountServiceImpl implements IAccountService interface
 type AccountServiceImpl struct {
-	accountRepo  repository.IAccountRepository
-	customerRepo repository.ICustomerRepository
+	accountRepo   repository.IAccountRepository
+	customerRepo  repository.ICustomerRepository
+	productRepo   repository.IProductRepository
+	ledgerService ILedgerService
+	numbers       accountnumber.Generator
+	pageTokens    *pagetoken.Signer
+	events        IEventRecorder
 }
 
 // NewAccountService creates a new instance of AccountService
-func NewAccountService(accountRepo repository.IAccountRepository, customerRepo repository.ICustomerRepository) IAccountService {
+func NewAccountService(accountRepo repository.IAccountRepository, customerRepo repository.ICustomerRepository, productRepo repository.IProductRepository, ledgerService ILedgerService, numbers accountnumber.Generator, pageTokens *pagetoken.Signer, events IEventRecorder) IAccountService {
 	return &AccountServiceImpl{
-		accountRepo:  accountRepo,
-		customerRepo: customerRepo,
+		accountRepo:   accountRepo,
+		customerRepo:  customerRepo,
+		productRepo:   productRepo,
+		ledgerService: ledgerService,
+		numbers:       numbers,
+		pageTokens:    pageTokens,
+		events:        events,
 	}
 }
 
-// CreateAccount creates a new account for a customer
+// CreateAccount opens a new account for a customer. The account type and
+// currency are checked against the product catalog, and the opening
+// deposit, if any, is posted together with the account.
 func (s *AccountServiceImpl) CreateAccount(ctx context.Context, req *pb.CreateAccountRequest) (*pb.CreateAccountResponse, error) {
+	ctx, span := tracer.Start(ctx, "AccountService.CreateAccount")
+	defer span.End()
+
 	if req.CustomerId == "" {
-		return nil, errors.New("customer ID is required")
+		return nil, invalidField("customer_id", "customer ID is required")
+	}
+
+	accountType := req.AccountType
+	if accountType == "" {
+		accountType = models.ProductSavings
+	}
+
+	currencyCode := defaultCurrency
+	if req.Currency != "" {
+		currency, err := money.Lookup(req.Currency)
+		if err != nil {
+			return nil, invalidField("currency", err.Error())
+		}
+		currencyCode = currency.Code
 	}
 
 	// Verify customer exists
 	customer, err := s.customerRepo.FindByID(ctx, req.CustomerId)
 	if err != nil {
-		return nil, errors.New("customer not found")
+		return nil, err
+	}
+
+	product, err := s.findProduct(ctx, "account_type", accountType)
+	if err != nil {
+		return nil, err
+	}
+	if !product.Active {
+		return nil, ErrProductInactive
+	}
+
+	offer, ok := product.Currency(currencyCode)
+	if !ok {
+		return nil, invalidField("currency", fmt.Sprintf("%s accounts are not offered in %s", productName(product), currencyCode))
+	}
+
+	if err := s.ensureBelowAccountLimit(ctx, customer.ID, product); err != nil {
+		return nil, err
+	}
+
+	var deposit int64
+	if req.OpeningDeposit != nil {
+		amount, currency, err := parseAmount("opening_deposit", req.OpeningDeposit)
+		if err != nil {
+			return nil, err
+		}
+		if currency != currencyCode {
+			return nil, invalidField("opening_deposit.currency", "opening deposit must be in the account currency")
+		}
+		deposit = amount
+	}
+	if deposit < offer.MinOpeningBalance {
+		return nil, invalidField("opening_deposit", fmt.Sprintf("%s accounts need an opening deposit of at least %s %s",
+			productName(product), currencyCode, money.Format(currencyCode, offer.MinOpeningBalance)))
 	}
 
-	// Generate unique account number
-	accountNumber := fmt.Sprintf("%s-%d", customer.Phone, time.Now().Unix())
+	accountNumber, err := s.numbers.Generate(ctx, product.NumberPrefix)
+	if err != nil {
+		return nil, fmt.Errorf("failed to generate account number: %w", err)
+	}
 
 	// Create account model
 	account := &models.Account{
 		ID:            uuid.New().String(),
 		AccountNumber: accountNumber,
+		IBAN:          s.numbers.IBAN(accountNumber),
 		CustomerID:    req.CustomerId,
-		Status:        "active",
-		Balance:       0.0,
+		Status:        models.AccountStatusActive,
+		Balance:       0,
 		OpenedAt:      time.Now(),
-		Currency:      "NPR",
-		AccountType:   "savings",
+		Currency:      currencyCode,
+		AccountType:   product.Code,
 		CreatedAt:     time.Now(),
 		UpdatedAt:     time.Now(),
 	}
 
-	// Save to database
-	if err := s.accountRepo.Create(ctx, account); err != nil {
-		return nil, errors.New("failed to create account")
+	// Save to database, together with the opening deposit
+	if deposit > 0 {
+		entry := &models.JournalEntry{
+			Currency:    currencyCode,
+			EntryType:   models.EntryTypeDeposit,
+			Description: "Opening deposit",
+			Postings: []models.Posting{
+				{AccountID: account.ID, Amount: deposit},
+				{AccountID: models.CashAccountID, Amount: -deposit},
+			},
+		}
+		if err := s.ledgerService.OpenAccount(ctx, account, entry); err != nil {
+			return nil, fmt.Errorf("failed to create account: %w", err)
+		}
+	} else if err := s.accountRepo.Create(ctx, account); err != nil {
+		return nil, fmt.Errorf("failed to create account: %w", err)
 	}
+	s.events.AccountOpened()
 
 	return &pb.CreateAccountResponse{
 		AccountId:     account.ID,
 		AccountNumber: account.AccountNumber,
+		Iban:          account.IBAN,
 		Message:       "Account created successfully",
 	}, nil
 }
 
-// GetAccount retrieves an account by ID
+// GetAccount retrieves an account by ID or account number
 func (s *AccountServiceImpl) GetAccount(ctx context.Context, req *pb.GetAccountRequest) (*pb.GetAccountResponse, error) {
-	if req.AccountId == "" {
-		return nil, errors.New("account ID is required")
-	}
+	ctx, span := tracer.Start(ctx, "AccountService.GetAccount")
+	defer span.End()
 
-	account, err := s.accountRepo.FindByID(ctx, req.AccountId)
+	var account *models.Account
+	var err error
+	switch {
+	case req.AccountId != "" && req.AccountNumber != "":
+		return nil, invalidField("account_number", "give either an account ID or an account number, not both")
+	case req.AccountId != "":
+		account, err = s.accountRepo.FindByID(ctx, req.AccountId)
+	case req.AccountNumber != "":
+		number := accountnumber.Normalize(req.AccountNumber)
+		if err := accountnumber.Validate(number); err != nil {
+			// Accounts opened before check digits keep their legacy numbers
+			if !accountnumber.IsLegacy(req.AccountNumber) {
+				return nil, invalidField("account_number", "account number is not valid")
+			}
+			number = req.AccountNumber
+		}
+		account, err = s.accountRepo.FindByAccountNumber(ctx, number)
+	default:
+		return nil, invalidField("account_id", "account ID or account number is required")
+	}
 	if err != nil {
 		return nil, err
 	}
 
-	return &pb.GetAccountResponse{
-		AccountId:     account.ID,
-		CustomerId:    account.CustomerID,
-		AccountNumber: account.AccountNumber,
-		AccountType:   account.AccountType,
-		Balance:       account.Balance,
-		Currency:      account.Currency,
-		Status:        account.Status,
-		CreatedAt:     account.CreatedAt.Format(time.RFC3339),
-		UpdatedAt:     account.UpdatedAt.Format(time.RFC3339),
-	}, nil
+	return toAccountResponse(account), nil
 }
 
-// UpdateAccount updates an existing account
-func (s *AccountServiceImpl) UpdateAccount(ctx context.Context, req *pb.UpdateAccountRequest) (*pb.UpdateAccountResponse, error) {
+// UpdateAccount updates an existing account in one transaction. A status
+// change follows the same rules as the dedicated status RPCs, and a new
+// account type the same product rules as CreateAccount.
+func (s *AccountServiceImpl) UpdateAccount(ctx context.Context, actorID string, req *pb.UpdateAccountRequest) (*pb.UpdateAccountResponse, error) {
+	ctx, span := tracer.Start(ctx, "AccountService.UpdateAccount")
+	defer span.End()
+
 	if req.AccountId == "" {
-		return nil, errors.New("account ID is required")
+		return nil, invalidField("account_id", "account ID is required")
 	}
 
 	// Find existing account
@@ -112,43 +231,89 @@ func (s *AccountServiceImpl) UpdateAccount(ctx context.Context, req *pb.UpdateAc
 		return nil, err
 	}
 
-	// Update fields
-	if req.AccountType != "" {
-		account.AccountType = req.AccountType
+	// A new account type must be an active product offered in the
+	// account's currency, with room for one more of the customer's accounts
+	var product *models.AccountProduct
+	var offer *models.ProductCurrency
+	if req.AccountType != "" && req.AccountType != account.AccountType {
+		product, err = s.findProduct(ctx, "account_type", req.AccountType)
+		if err != nil {
+			return nil, err
+		}
+		if !product.Active {
+			return nil, ErrProductInactive
+		}
+		var ok bool
+		if offer, ok = product.Currency(account.Currency); !ok {
+			return nil, invalidField("account_type", fmt.Sprintf("%s accounts are not offered in %s", productName(product), account.Currency))
+		}
+		if err := s.ensureBelowAccountLimit(ctx, account.CustomerID, product); err != nil {
+			return nil, err
+		}
 	}
-	if req.Status != "" {
-		account.Status = req.Status
+
+	var change *models.AccountStatusChange
+	if req.Status != "" && req.Status != account.Status {
+		change, err = newStatusChange(actorID, account.ID, req.Status, req.StatusReason, req.StatusNote, "status_reason")
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	// Recheck against the locked account, which may have changed since it
+	// was read
+	update := func(locked *models.Account) error {
+		if change != nil {
+			if err := checkTransition(locked, change.ToStatus); err != nil {
+				return err
+			}
+		}
+		if product != nil {
+			if locked.Balance < offer.MinOpeningBalance {
+				return &repository.ConflictError{
+					Reason: "BALANCE_BELOW_MINIMUM",
+					Message: fmt.Sprintf("%s accounts need a balance of at least %s %s",
+						productName(product), locked.Currency, money.Format(locked.Currency, offer.MinOpeningBalance)),
+				}
+			}
+			locked.AccountType = product.Code
+		}
+		return nil
 	}
-	account.UpdatedAt = time.Now()
 
-	// Save changes
-	if err := s.accountRepo.Update(ctx, account); err != nil {
-		return nil, errors.New("failed to update account")
+	account, err = s.accountRepo.Update(ctx, account.ID, update, change)
+	if err != nil {
+		return nil, fmt.Errorf("failed to update account: %w", err)
 	}
 
 	return &pb.UpdateAccountResponse{
 		Message: "Account updated successfully",
-		Account: &pb.GetAccountResponse{
-			AccountId:     account.ID,
-			AccountNumber: account.AccountNumber,
-			CustomerId:    account.CustomerID,
-			AccountType:   account.AccountType,
-			Balance:       account.Balance,
-			Currency:      account.Currency,
-			Status:        account.Status,
-			CreatedAt:     account.CreatedAt.Format(time.RFC3339),
-			UpdatedAt:     account.UpdatedAt.Format(time.RFC3339),
-		},
+		Account: toAccountResponse(account),
 	}, nil
 }
 
-// DeleteAccount deletes an account by ID
+// DeleteAccount deletes an account by ID. Only closed accounts may be
+// deleted, so every account leaves through CloseAccount, with its zero
+// balance check and status history entry.
 func (s *AccountServiceImpl) DeleteAccount(ctx context.Context, req *pb.DeleteAccountRequest) (*pb.DeleteAccountResponse, error) {
+	ctx, span := tracer.Start(ctx, "AccountService.DeleteAccount")
+	defer span.End()
+
 	if req.AccountId == "" {
-		return nil, errors.New("account ID is required")
+		return nil, invalidField("account_id", "account ID is required")
 	}
 
-	if err := s.accountRepo.Delete(ctx, req.AccountId); err != nil {
+	guard := func(account *models.Account) error {
+		if account.Status != models.AccountStatusClosed {
+			return ErrAccountNotClosed
+		}
+		if account.Balance != 0 {
+			return ErrBalanceNotZero
+		}
+		return nil
+	}
+
+	if err := s.accountRepo.Delete(ctx, req.AccountId, guard); err != nil {
 		return nil, err
 	}
 
@@ -157,44 +322,371 @@ func (s *AccountServiceImpl) DeleteAccount(ctx context.Context, req *pb.DeleteAc
 	}, nil
 }
 
-// ListAccounts lists all accounts with pagination
+// ListAccounts lists all accounts, oldest first, one page at a time
 func (s *AccountServiceImpl) ListAccounts(ctx context.Context, req *pb.ListAccountRequest) (*pb.ListAccountResponse, error) {
-	// Set default pagination values
-	pageSize := int(req.PageSize)
-	if pageSize <= 0 {
-		pageSize = 10
+	ctx, span := tracer.Start(ctx, "AccountService.ListAccounts")
+	defer span.End()
+
+	page, err := parsePage("accounts", req.PageNumber, req.PageSize, req.PageToken, req.TotalCountMode, s.pageTokens)
+	if err != nil {
+		return nil, err
+	}
+
+	// Fetch accounts
+	accounts, err := s.accountRepo.FindAll(ctx, page.query)
+	if err != nil {
+		return nil, fmt.Errorf("failed to retrieve accounts: %w", err)
 	}
 
-	pageNumber := int(req.PageNumber)
-	if pageNumber <= 0 {
-		pageNumber = 1
+	total, estimated, err := totalCount(ctx, req.TotalCountMode, s.accountRepo.Count, s.accountRepo.EstimateCount)
+	if err != nil {
+		return nil, fmt.Errorf("failed to count accounts: %w", err)
+	}
+
+	return s.toAccountList(page, accounts, total, estimated), nil
+}
+
+// ListCustomerAccounts lists the accounts of one customer, oldest first,
+// one page at a time. Their total count is always exact.
+func (s *AccountServiceImpl) ListCustomerAccounts(ctx context.Context, customerID string, req *pb.ListAccountRequest) (*pb.ListAccountResponse, error) {
+	ctx, span := tracer.Start(ctx, "AccountService.ListCustomerAccounts")
+	defer span.End()
+
+	if customerID == "" {
+		return nil, invalidField("customer_id", "customer ID is required")
 	}
 
-	offset := (pageNumber - 1) * pageSize
+	// Tokens of one customer's list are no good for another's
+	page, err := parsePage("accounts:customer:"+customerID, req.PageNumber, req.PageSize, req.PageToken, req.TotalCountMode, s.pageTokens)
+	if err != nil {
+		return nil, err
+	}
 
 	// Fetch accounts
-	accounts, err := s.accountRepo.FindAll(ctx, pageSize, offset)
+	accounts, err := s.accountRepo.FindByCustomerID(ctx, customerID, page.query)
+	if err != nil {
+		return nil, fmt.Errorf("failed to retrieve accounts: %w", err)
+	}
+
+	countAccounts := func(ctx context.Context) (int64, error) {
+		return s.accountRepo.CountByCustomerID(ctx, customerID)
+	}
+	total, estimated, err := totalCount(ctx, req.TotalCountMode, countAccounts, nil)
 	if err != nil {
-		return nil, errors.New("failed to retrieve accounts")
+		return nil, fmt.Errorf("failed to count accounts: %w", err)
 	}
 
+	return s.toAccountList(page, accounts, total, estimated), nil
+}
+
+// toAccountList converts a page of accounts to the list response
+func (s *AccountServiceImpl) toAccountList(page *listPage, accounts []*models.Account, total int32, estimated bool) *pb.ListAccountResponse {
+	accounts, nextPageToken := trimPage(page, accounts, s.pageTokens, func(a *models.Account) repository.Cursor {
+		return repository.Cursor{CreatedAt: a.CreatedAt, ID: a.ID}
+	})
+
 	// Convert to response format
 	var accountResponses []*pb.GetAccountResponse
 	for _, account := range accounts {
-		accountResponses = append(accountResponses, &pb.GetAccountResponse{
-			AccountId:     account.ID,
-			CustomerId:    account.CustomerID,
-			AccountNumber: account.AccountNumber,
-			AccountType:   account.AccountType,
-			Balance:       account.Balance,
-			Currency:      account.Currency,
-			Status:        account.Status,
-			CreatedAt:     account.CreatedAt.Format(time.RFC3339),
-			UpdatedAt:     account.UpdatedAt.Format(time.RFC3339),
-		})
+		accountResponses = append(accountResponses, toAccountResponse(account))
 	}
 
 	return &pb.ListAccountResponse{
-		Accounts: accountResponses,
+		Accounts:            accountResponses,
+		TotalCount:          total,
+		NextPageToken:       nextPageToken,
+		TotalCountEstimated: estimated,
+	}
+}
+
+// Deposit credits an account with money received by the bank
+func (s *AccountServiceImpl) Deposit(ctx context.Context, req *pb.DepositRequest) (*pb.DepositResponse, error) {
+	ctx, span := tracer.Start(ctx, "AccountService.Deposit")
+	defer span.End()
+
+	if err := checkAccountID("account_id", req.AccountId, "account ID is required"); err != nil {
+		return nil, err
+	}
+
+	amount, currency, err := parseAmount("amount", req.Amount)
+	if err != nil {
+		return nil, err
+	}
+
+	entry := &models.JournalEntry{
+		Currency:    currency,
+		EntryType:   models.EntryTypeDeposit,
+		Reference:   req.Reference,
+		Description: req.Description,
+		Postings: []models.Posting{
+			{AccountID: req.AccountId, Amount: amount},
+			{AccountID: models.CashAccountID, Amount: -amount},
+		},
+	}
+
+	guard := func(accounts map[string]*models.Account) error {
+		account := accounts[req.AccountId]
+		if account == nil {
+			return &repository.NotFoundError{Resource: "account"}
+		}
+		if !acceptsCredits(account) {
+			return ErrAccountNotTransactable
+		}
+		return nil
+	}
+
+	if err := s.ledgerService.PostEntry(ctx, entry, guard); err != nil {
+		return nil, err
+	}
+
+	account, err := s.accountRepo.FindByID(ctx, req.AccountId)
+	if err != nil {
+		return nil, afterCommit("failed to reload account", err)
+	}
+
+	return &pb.DepositResponse{
+		TransactionId: entry.ID,
+		Account:       toAccountResponse(account),
+		Message:       "Deposit successful",
 	}, nil
 }
+
+// Withdraw debits an account with money paid out by the bank
+func (s *AccountServiceImpl) Withdraw(ctx context.Context, req *pb.WithdrawRequest) (*pb.WithdrawResponse, error) {
+	ctx, span := tracer.Start(ctx, "AccountService.Withdraw")
+	defer span.End()
+
+	if err := checkAccountID("account_id", req.AccountId, "account ID is required"); err != nil {
+		return nil, err
+	}
+
+	amount, currency, err := parseAmount("amount", req.Amount)
+	if err != nil {
+		return nil, err
+	}
+
+	entry := &models.JournalEntry{
+		Currency:    currency,
+		EntryType:   models.EntryTypeWithdrawal,
+		Reference:   req.Reference,
+		Description: req.Description,
+		Postings: []models.Posting{
+			{AccountID: req.AccountId, Amount: -amount},
+			{AccountID: models.CashAccountID, Amount: amount},
+		},
+	}
+
+	guard := func(accounts map[string]*models.Account) error {
+		account := accounts[req.AccountId]
+		if account == nil {
+			return &repository.NotFoundError{Resource: "account"}
+		}
+		if err := ensureCanDebit(account, amount); err != nil {
+			return err
+		}
+		return s.ensureWithdrawalsAllowed(ctx, account)
+	}
+
+	if err := s.ledgerService.PostEntry(ctx, entry, guard); err != nil {
+		return nil, err
+	}
+
+	account, err := s.accountRepo.FindByID(ctx, req.AccountId)
+	if err != nil {
+		return nil, afterCommit("failed to reload account", err)
+	}
+
+	return &pb.WithdrawResponse{
+		TransactionId: entry.ID,
+		Account:       toAccountResponse(account),
+		Message:       "Withdrawal successful",
+	}, nil
+}
+
+// Transfer moves money from one account to another
+func (s *AccountServiceImpl) Transfer(ctx context.Context, req *pb.TransferRequest) (*pb.TransferResponse, error) {
+	ctx, span := tracer.Start(ctx, "AccountService.Transfer")
+	defer span.End()
+
+	if err := checkAccountID("from_account_id", req.FromAccountId, "source account ID is required"); err != nil {
+		return nil, err
+	}
+
+	if err := checkAccountID("to_account_id", req.ToAccountId, "destination account ID is required"); err != nil {
+		return nil, err
+	}
+
+	if req.FromAccountId == req.ToAccountId {
+		return nil, invalidField("to_account_id", "cannot transfer to the same account")
+	}
+
+	amount, currency, err := parseAmount("amount", req.Amount)
+	if err != nil {
+		return nil, err
+	}
+
+	entry := &models.JournalEntry{
+		Currency:    currency,
+		EntryType:   models.EntryTypeTransfer,
+		Reference:   req.Reference,
+		Description: req.Description,
+		Postings: []models.Posting{
+			{AccountID: req.FromAccountId, Amount: -amount},
+			{AccountID: req.ToAccountId, Amount: amount},
+		},
+	}
+
+	guard := func(accounts map[string]*models.Account) error {
+		from, to := accounts[req.FromAccountId], accounts[req.ToAccountId]
+		if from == nil || to == nil {
+			return &repository.NotFoundError{Resource: "account"}
+		}
+		if from.Currency != to.Currency {
+			return ErrCurrencyMismatch
+		}
+		if !acceptsCredits(to) {
+			return ErrAccountNotTransactable
+		}
+		if err := ensureCanDebit(from, amount); err != nil {
+			return err
+		}
+		return s.ensureWithdrawalsAllowed(ctx, from)
+	}
+
+	if err := s.ledgerService.PostEntry(ctx, entry, guard); err != nil {
+		return nil, err
+	}
+
+	from, err := s.accountRepo.FindByID(ctx, req.FromAccountId)
+	if err != nil {
+		return nil, afterCommit("failed to reload account", err)
+	}
+
+	to, err := s.accountRepo.FindByID(ctx, req.ToAccountId)
+	if err != nil {
+		return nil, afterCommit("failed to reload account", err)
+	}
+
+	return &pb.TransferResponse{
+		TransactionId: entry.ID,
+		FromAccount:   toAccountResponse(from),
+		ToAccount:     toAccountResponse(to),
+		Message:       "Transfer successful",
+	}, nil
+}
+
+// findProduct looks up the product for an account type; an unknown type is
+// reported against field
+func (s *AccountServiceImpl) findProduct(ctx context.Context, field, code string) (*models.AccountProduct, error) {
+	product, err := s.productRepo.FindByCode(ctx, code)
+	if errors.Is(err, ErrNotFound) {
+		return nil, invalidField(field, fmt.Sprintf("unknown account type %q", code))
+	}
+	return product, err
+}
+
+// ensureBelowAccountLimit checks that the customer may hold one more
+// account of the product
+func (s *AccountServiceImpl) ensureBelowAccountLimit(ctx context.Context, customerID string, product *models.AccountProduct) error {
+	if product.MaxAccountsPerCustomer <= 0 {
+		return nil
+	}
+
+	count, err := s.accountRepo.CountByCustomerAndType(ctx, customerID, product.Code)
+	if err != nil {
+		return err
+	}
+	if count >= int64(product.MaxAccountsPerCustomer) {
+		return &repository.ConflictError{
+			Reason:  "ACCOUNT_LIMIT_REACHED",
+			Message: fmt.Sprintf("customer already holds the maximum of %d %s accounts", product.MaxAccountsPerCustomer, productName(product)),
+		}
+	}
+	return nil
+}
+
+// ensureWithdrawalsAllowed checks that the account's product lets money
+// leave the account. Accounts of types missing from the catalog have no
+// product rules.
+func (s *AccountServiceImpl) ensureWithdrawalsAllowed(ctx context.Context, account *models.Account) error {
+	product, err := s.productRepo.FindByCode(ctx, account.AccountType)
+	if errors.Is(err, ErrNotFound) {
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+	if !product.AllowWithdrawals {
+		return &repository.ConflictError{
+			Reason:  "WITHDRAWALS_NOT_ALLOWED",
+			Message: fmt.Sprintf("withdrawals are not allowed from %s accounts", productName(product)),
+		}
+	}
+	return nil
+}
+
+// checkAccountID checks that field holds the ID of a customer account.
+// Internal ledger accounts are only posted to by the service itself.
+func checkAccountID(field, accountID, required string) error {
+	if accountID == "" {
+		return invalidField(field, required)
+	}
+	if models.IsInternalAccount(accountID) {
+		return invalidField(field, "internal ledger accounts cannot be used")
+	}
+	return nil
+}
+
+// parseAmount checks that the money amount in field is positive and in a
+// supported currency
+func parseAmount(field string, amount *pb.Money) (int64, string, error) {
+	name := strings.ReplaceAll(field, "_", " ")
+	if amount == nil {
+		return 0, "", invalidField(field, name+" is required")
+	}
+	currency, err := money.Lookup(amount.Currency)
+	if err != nil {
+		return 0, "", invalidField(field+".currency", err.Error())
+	}
+	if amount.MinorUnits <= 0 {
+		return 0, "", invalidField(field+".minor_units", name+" must be greater than zero")
+	}
+	return amount.MinorUnits, currency.Code, nil
+}
+
+// ensureCanDebit checks that the account is active and holds at least amount
+func ensureCanDebit(account *models.Account, amount int64) error {
+	if !acceptsDebits(account) {
+		return ErrAccountNotTransactable
+	}
+	if account.Balance < amount {
+		return ErrInsufficientFunds
+	}
+	return nil
+}
+
+// toAccountResponse converts an account model to its response message
+func toAccountResponse(account *models.Account) *pb.GetAccountResponse {
+	return &pb.GetAccountResponse{
+		AccountId:     account.ID,
+		AccountNumber: account.AccountNumber,
+		Iban:          account.IBAN,
+		CustomerId:    account.CustomerID,
+		AccountType:   account.AccountType,
+		Balance:       money.ToFloat(account.Currency, account.Balance),
+		BalanceAmount: toMoney(account.Currency, account.Balance),
+		Currency:      account.Currency,
+		Status:        account.Status,
+		CreatedAt:     account.CreatedAt.Format(time.RFC3339),
+		UpdatedAt:     account.UpdatedAt.Format(time.RFC3339),
+	}
+}
+
+// toMoney converts minor units to the Money message
+func toMoney(currency string, units int64) *pb.Money {
+	return &pb.Money{
+		Currency:   currency,
+		MinorUnits: units,
+		Formatted:  money.Format(currency, units),
+	}
+}
@@ -0,0 +1,51 @@
+package service_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/paudelanil/grpc-crud/internal/service"
+	"github.com/paudelanil/grpc-crud/models"
+	"github.com/paudelanil/grpc-crud/pb"
+)
+
+func TestMoneyMovementsRejectInternalAccounts(t *testing.T) {
+	// Internal IDs are rejected before any repository is used
+	accountService := service.NewAccountService(nil, nil, nil, nil, nil, nil, service.NopEventRecorder{})
+	amount := &pb.Money{Currency: "NPR", MinorUnits: 100}
+	ctx := context.Background()
+
+	tests := []struct {
+		name      string
+		call      func() error
+		wantField string
+	}{
+		{"deposit", func() error {
+			_, err := accountService.Deposit(ctx, &pb.DepositRequest{AccountId: models.CashAccountID, Amount: amount})
+			return err
+		}, "account_id"},
+		{"withdraw", func() error {
+			_, err := accountService.Withdraw(ctx, &pb.WithdrawRequest{AccountId: models.CashAccountID, Amount: amount})
+			return err
+		}, "account_id"},
+		{"transfer from", func() error {
+			_, err := accountService.Transfer(ctx, &pb.TransferRequest{FromAccountId: models.CashAccountID, ToAccountId: "internal:other", Amount: amount})
+			return err
+		}, "from_account_id"},
+		{"transfer to", func() error {
+			_, err := accountService.Transfer(ctx, &pb.TransferRequest{FromAccountId: "account-1", ToAccountId: models.CashAccountID, Amount: amount})
+			return err
+		}, "to_account_id"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := tt.call()
+			var invalid *service.ValidationError
+			if !errors.As(err, &invalid) || len(invalid.Violations) != 1 || invalid.Violations[0].Field != tt.wantField {
+				t.Fatalf("error = %v, want a validation error on %s", err, tt.wantField)
+			}
+		})
+	}
+}
@@ -2,11 +2,15 @@ package service
 
 import (
 	"context"
+	"crypto/hmac"
+	"crypto/sha256"
 	"errors"
+	"fmt"
 	"time"
 
 	"github.com/golang-jwt/jwt/v5"
 	"github.com/google/uuid"
+	"github.com/paudelanil/grpc-crud/internal/logging"
 	"github.com/paudelanil/grpc-crud/internal/repository"
 	"github.com/paudelanil/grpc-crud/models"
 	"github.com/paudelanil/grpc-crud/pb"
@@ -16,31 +20,81 @@ import (
 // IAuthService defines the interface for authentication operations
 type IAuthService interface {
 	Login(ctx context.Context, req *pb.UserLoginRequest) (*pb.UserLoginResponse, error)
-	Logout(ctx context.Context, req *pb.UserLogoutRequest) (*pb.UserLogoutResponse, error)
+	Logout(ctx context.Context, callerID string, req *pb.UserLogoutRequest) (*pb.UserLogoutResponse, error)
 	RefreshToken(ctx context.Context, req *pb.TokenRequest) (*pb.TokenResponse, error)
 	Register(ctx context.Context, username, email, password string) error
-	ValidateToken(tokenString string) (*Claims, error)
+	UpdateUserRole(ctx context.Context, userID, role string) error
+	PromoteAdmin(ctx context.Context, username string) (bool, error)
+	LinkCustomer(ctx context.Context, userID, customerID string) error
+	ValidateAccessToken(ctx context.Context, tokenString string) (*Claims, error)
+	PurgeExpiredSessions(ctx context.Context) error
 }
 
 // AuthService implements IAuthService interface
 type AuthService struct {
-	userRepo  repository.IUserRepository
-	jwtSecret string
+	userRepo     repository.IUserRepository
+	customerRepo repository.ICustomerRepository
+	sessionRepo  repository.ISessionRepository
+	jwtSecret    string
+	accessTTL    time.Duration
+	refreshTTL   time.Duration
+	events       IEventRecorder
 }
 
-// Claims represents JWT claims
+var (
+	// ErrInvalidCredentials is returned when the username or password is wrong
+	ErrInvalidCredentials = &AuthError{Reason: "INVALID_CREDENTIALS", Message: "invalid username or password"}
+	// ErrUserInactive is returned when a deactivated user logs in or refreshes a token
+	ErrUserInactive = &AuthError{Reason: "USER_INACTIVE", Message: "user account is inactive"}
+	// ErrInvalidToken is returned for malformed, expired or revoked tokens
+	ErrInvalidToken = &AuthError{Reason: "INVALID_TOKEN", Message: "invalid or expired token"}
+	// ErrTokenReused is returned when a refresh token is presented a second time
+	ErrTokenReused = &AuthError{Reason: "TOKEN_REUSED", Message: "refresh token has already been used"}
+	// ErrNotTokenOwner is returned when a user logs out a token issued to someone else
+	ErrNotTokenOwner = &PermissionError{Reason: "NOT_TOKEN_OWNER", Message: "token belongs to another user"}
+)
+
+// Token types carried in the typ claim
+const (
+	TokenTypeAccess  = "access"
+	TokenTypeRefresh = "refresh"
+)
+
+// tokenAudiences pins each token type to the only place it is accepted
+var tokenAudiences = map[string]string{
+	TokenTypeAccess:  "grpc-crud/api",
+	TokenTypeRefresh: "grpc-crud/token-refresh",
+}
+
+// Claims represents JWT claims. RegisteredClaims.ID carries the token ID (jti).
 type Claims struct {
-	UserID   string `json:"user_id"`
-	Username string `json:"username"`
-	Email    string `json:"email"`
+	UserID     string `json:"user_id"`
+	Username   string `json:"username"`
+	Email      string `json:"email"`
+	Role       string `json:"role"`
+	CustomerID string `json:"customer_id,omitempty"`
+	SessionID  string `json:"sid"`
+	TokenType  string `json:"typ"`
 	jwt.RegisteredClaims
 }
 
 // NewAuthService creates a new instance of AuthService
-func NewAuthService(userRepo repository.IUserRepository, jwtSecret string) IAuthService {
+func NewAuthService(
+	userRepo repository.IUserRepository,
+	customerRepo repository.ICustomerRepository,
+	sessionRepo repository.ISessionRepository,
+	jwtSecret string,
+	accessTTL, refreshTTL time.Duration,
+	events IEventRecorder,
+) IAuthService {
 	return &AuthService{
-		userRepo:  userRepo,
-		jwtSecret: jwtSecret,
+		userRepo:     userRepo,
+		customerRepo: customerRepo,
+		sessionRepo:  sessionRepo,
+		jwtSecret:    jwtSecret,
+		accessTTL:    accessTTL,
+		refreshTTL:   refreshTTL,
+		events:       events,
 	}
 }
 
@@ -49,37 +103,62 @@ func (s *AuthService) Login(
 	ctx context.Context,
 	req *pb.UserLoginRequest,
 ) (*pb.UserLoginResponse, error) {
+	ctx, span := tracer.Start(ctx, "AuthService.Login")
+	defer span.End()
+
 	// Validate input
-	if req.Username == "" || req.Password == "" {
-		return nil, errors.New("username and password are required")
+	if req.Username == "" {
+		return nil, invalidField("username", "username is required")
+	}
+	if req.Password == "" {
+		return nil, invalidField("password", "password is required")
 	}
 
 	// Find user by username
 	user, err := s.userRepo.FindByUsername(ctx, req.Username)
 	if err != nil {
-		return nil, errors.New("invalid username or password")
+		if !errors.Is(err, ErrNotFound) {
+			return nil, err
+		}
+		s.events.LoginFailed("unknown_user")
+		return nil, ErrInvalidCredentials
 	}
 
 	// Check if user is active
 	if !user.IsActive {
-		return nil, errors.New("user account is inactive")
+		s.events.LoginFailed("inactive")
+		return nil, ErrUserInactive
 	}
 
 	// Verify password
 	if err := bcrypt.CompareHashAndPassword([]byte(user.Password), []byte(req.Password)); err != nil {
-		return nil, errors.New("invalid username or password")
+		s.events.LoginFailed("bad_password")
+		return nil, ErrInvalidCredentials
+	}
+
+	// Start a session that owns the refresh token family
+	session := &models.Session{
+		ID:             uuid.New().String(),
+		UserID:         user.ID,
+		RefreshTokenID: uuid.New().String(),
+		ExpiresAt:      time.Now().Add(s.refreshTTL),
+		CreatedAt:      time.Now(),
+		UpdatedAt:      time.Now(),
+	}
+	if err := s.sessionRepo.Create(ctx, session); err != nil {
+		return nil, fmt.Errorf("failed to create session: %w", err)
 	}
 
-	// Generate access token (expires in 15 minutes)
-	accessToken, err := s.generateToken(user, 15*time.Minute)
+	// Generate access token
+	accessToken, err := s.generateToken(user, TokenTypeAccess, session.ID, uuid.New().String(), s.accessTTL)
 	if err != nil {
-		return nil, errors.New("failed to generate access token")
+		return nil, fmt.Errorf("failed to generate access token: %w", err)
 	}
 
-	// Generate refresh token (expires in 7 days)
-	refreshToken, err := s.generateToken(user, 7*24*time.Hour)
+	// Generate refresh token (expires with the session)
+	refreshToken, err := s.generateToken(user, TokenTypeRefresh, session.ID, session.RefreshTokenID, s.refreshTTL)
 	if err != nil {
-		return nil, errors.New("failed to generate refresh token")
+		return nil, fmt.Errorf("failed to generate refresh token: %w", err)
 	}
 
 	return &pb.UserLoginResponse{
@@ -89,20 +168,44 @@ func (s *AuthService) Login(
 	}, nil
 }
 
-// Logout handles user logout (in a real app, you'd blacklist the token)
+// Logout revokes the access token and ends its session, which also
+// invalidates every refresh token issued for it. Callers may only log out
+// their own tokens.
 func (s *AuthService) Logout(
 	ctx context.Context,
+	callerID string,
 	req *pb.UserLogoutRequest,
 ) (*pb.UserLogoutResponse, error) {
+	ctx, span := tracer.Start(ctx, "AuthService.Logout")
+	defer span.End()
+
 	// Validate the token
 	if req.AccessToken == "" {
-		return nil, errors.New("access token is required")
+		return nil, invalidField("access_token", "access token is required")
 	}
 
-	// In a production system, you would:
-	// 1. Validate the token
-	// 2. Add it to a blacklist/redis cache
-	// 3. Set expiry on the blacklist entry
+	claims, err := s.ValidateAccessToken(ctx, req.AccessToken)
+	if err != nil {
+		return nil, ErrInvalidToken
+	}
+	if claims.UserID != callerID {
+		return nil, ErrNotTokenOwner
+	}
+
+	// Keep the revocation until the token would have expired anyway
+	revoked := &models.RevokedToken{
+		TokenID:   claims.ID,
+		UserID:    claims.UserID,
+		ExpiresAt: claims.ExpiresAt.Time,
+		RevokedAt: time.Now(),
+	}
+	if err := s.sessionRepo.RevokeToken(ctx, revoked); err != nil {
+		return nil, fmt.Errorf("failed to revoke token: %w", err)
+	}
+
+	if err := s.sessionRepo.Revoke(ctx, claims.SessionID, "logout"); err != nil {
+		return nil, fmt.Errorf("failed to end session: %w", err)
+	}
 
 	return &pb.UserLogoutResponse{
 		Message: "Logout successful",
@@ -114,36 +217,71 @@ func (s *AuthService) RefreshToken(
 	ctx context.Context,
 	req *pb.TokenRequest,
 ) (*pb.TokenResponse, error) {
+	ctx, span := tracer.Start(ctx, "AuthService.RefreshToken")
+	defer span.End()
+
 	if req.RefreshToken == "" {
-		return nil, errors.New("refresh token is required")
+		return nil, invalidField("refresh_token", "refresh token is required")
 	}
 
 	// Parse and validate the refresh token
-	claims, err := s.ValidateToken(req.RefreshToken)
+	claims, err := s.validateToken(ctx, req.RefreshToken, TokenTypeRefresh)
+	if err != nil {
+		return nil, ErrInvalidToken
+	}
+
+	session, err := s.sessionRepo.FindByID(ctx, claims.SessionID)
 	if err != nil {
-		return nil, errors.New("invalid or expired refresh token")
+		return nil, ErrInvalidToken
+	}
+
+	// Refresh tokens are single use. Presenting an older one means it was
+	// stolen or replayed, so the whole session is killed.
+	if session.RefreshTokenID != claims.ID {
+		logging.FromContext(ctx).WarnContext(ctx, "refresh token reuse detected, revoking session", "session_id", session.ID, "user_id", session.UserID)
+		if err := s.sessionRepo.Revoke(ctx, session.ID, "refresh token reuse"); err != nil {
+			return nil, fmt.Errorf("failed to end session: %w", err)
+		}
+		return nil, ErrTokenReused
 	}
 
 	// Get user from database
 	user, err := s.userRepo.FindByID(ctx, claims.UserID)
 	if err != nil {
-		return nil, errors.New("user not found")
+		if errors.Is(err, ErrNotFound) {
+			return nil, ErrInvalidToken
+		}
+		return nil, err
 	}
 
 	if !user.IsActive {
-		return nil, errors.New("user account is inactive")
+		return nil, ErrUserInactive
+	}
+
+	// Rotate the refresh token; losing the race to a concurrent refresh is reuse too
+	nextRefreshTokenID := uuid.New().String()
+	rotated, err := s.sessionRepo.RotateRefreshToken(ctx, session.ID, claims.ID, nextRefreshTokenID, time.Now().Add(s.refreshTTL))
+	if err != nil {
+		return nil, fmt.Errorf("failed to rotate refresh token: %w", err)
+	}
+	if !rotated {
+		logging.FromContext(ctx).WarnContext(ctx, "refresh token reuse detected, revoking session", "session_id", session.ID, "user_id", session.UserID)
+		if err := s.sessionRepo.Revoke(ctx, session.ID, "refresh token reuse"); err != nil {
+			return nil, fmt.Errorf("failed to end session: %w", err)
+		}
+		return nil, ErrTokenReused
 	}
 
 	// Generate new access token
-	accessToken, err := s.generateToken(user, 15*time.Minute)
+	accessToken, err := s.generateToken(user, TokenTypeAccess, session.ID, uuid.New().String(), s.accessTTL)
 	if err != nil {
-		return nil, errors.New("failed to generate access token")
+		return nil, fmt.Errorf("failed to generate access token: %w", err)
 	}
 
 	// Generate new refresh token
-	refreshToken, err := s.generateToken(user, 7*24*time.Hour)
+	refreshToken, err := s.generateToken(user, TokenTypeRefresh, session.ID, nextRefreshTokenID, s.refreshTTL)
 	if err != nil {
-		return nil, errors.New("failed to generate refresh token")
+		return nil, fmt.Errorf("failed to generate refresh token: %w", err)
 	}
 
 	return &pb.TokenResponse{
@@ -154,33 +292,28 @@ func (s *AuthService) RefreshToken(
 
 // Register creates a new user account
 func (s *AuthService) Register(ctx context.Context, username, email, password string) error {
+	ctx, span := tracer.Start(ctx, "AuthService.Register")
+	defer span.End()
+
 	// Validate input
-	if username == "" || email == "" || password == "" {
-		return errors.New("username, email, and password are required")
+	var violations []FieldViolation
+	if username == "" {
+		violations = append(violations, FieldViolation{Field: "username", Description: "username is required"})
 	}
-
-	// Check if username is taken
-	taken, err := s.userRepo.IsUsernameTaken(ctx, username)
-	if err != nil {
-		return err
+	if email == "" {
+		violations = append(violations, FieldViolation{Field: "email", Description: "email is required"})
 	}
-	if taken {
-		return errors.New("username is already taken")
+	if password == "" {
+		violations = append(violations, FieldViolation{Field: "password", Description: "password is required"})
 	}
-
-	// Check if email is taken
-	taken, err = s.userRepo.IsEmailTaken(ctx, email)
-	if err != nil {
-		return err
-	}
-	if taken {
-		return errors.New("email is already taken")
+	if len(violations) > 0 {
+		return &ValidationError{Violations: violations}
 	}
 
 	// Hash password
 	hashedPassword, err := bcrypt.GenerateFromPassword([]byte(password), bcrypt.DefaultCost)
 	if err != nil {
-		return errors.New("failed to hash password")
+		return fmt.Errorf("failed to hash password: %w", err)
 	}
 
 	// Create user
@@ -189,19 +322,111 @@ func (s *AuthService) Register(ctx context.Context, username, email, password st
 		Username:  username,
 		Email:     email,
 		Password:  string(hashedPassword),
+		Role:      models.RoleCustomer,
 		IsActive:  true,
 		CreatedAt: time.Now(),
 		UpdatedAt: time.Now(),
 	}
 
+	// The unique indexes reject a taken username or email
 	return s.userRepo.Create(ctx, user)
 }
 
-// ValidateToken validates a JWT token and returns the claims
-func (s *AuthService) ValidateToken(tokenString string) (*Claims, error) {
+// UpdateUserRole changes the role of a user. The new role is carried by
+// tokens issued from the next login or refresh onwards.
+func (s *AuthService) UpdateUserRole(ctx context.Context, userID, role string) error {
+	ctx, span := tracer.Start(ctx, "AuthService.UpdateUserRole")
+	defer span.End()
+
+	if userID == "" {
+		return invalidField("user_id", "user ID is required")
+	}
+
+	if !models.IsValidRole(role) {
+		return invalidField("role", "role must be one of admin, teller, auditor or customer")
+	}
+
+	user, err := s.userRepo.FindByID(ctx, userID)
+	if err != nil {
+		return err
+	}
+
+	user.Role = role
+	user.UpdatedAt = time.Now()
+
+	return s.userRepo.Update(ctx, user)
+}
+
+// PromoteAdmin gives the admin role to the user with username, reporting
+// whether the role changed. It bootstraps the first admin, who can then
+// assign roles through UpdateUserRole.
+func (s *AuthService) PromoteAdmin(ctx context.Context, username string) (bool, error) {
+	ctx, span := tracer.Start(ctx, "AuthService.PromoteAdmin")
+	defer span.End()
+
+	user, err := s.userRepo.FindByUsername(ctx, username)
+	if err != nil {
+		return false, err
+	}
+	if user.Role == models.RoleAdmin {
+		return false, nil
+	}
+
+	user.Role = models.RoleAdmin
+	user.UpdatedAt = time.Now()
+	if err := s.userRepo.Update(ctx, user); err != nil {
+		return false, err
+	}
+	return true, nil
+}
+
+// LinkCustomer links a user to the bank customer it acts for. Like role
+// changes, the link reaches the user's tokens on the next login or refresh.
+func (s *AuthService) LinkCustomer(ctx context.Context, userID, customerID string) error {
+	ctx, span := tracer.Start(ctx, "AuthService.LinkCustomer")
+	defer span.End()
+
+	if userID == "" {
+		return invalidField("user_id", "user ID is required")
+	}
+
+	if customerID == "" {
+		return invalidField("customer_id", "customer ID is required")
+	}
+
+	user, err := s.userRepo.FindByID(ctx, userID)
+	if err != nil {
+		return err
+	}
+
+	if _, err := s.customerRepo.FindByID(ctx, customerID); err != nil {
+		return err
+	}
+
+	user.CustomerID = &customerID
+	user.UpdatedAt = time.Now()
+
+	return s.userRepo.Update(ctx, user)
+}
+
+// ValidateAccessToken validates an access token and returns its claims.
+// Refresh tokens are rejected.
+func (s *AuthService) ValidateAccessToken(ctx context.Context, tokenString string) (*Claims, error) {
+	ctx, span := tracer.Start(ctx, "AuthService.ValidateAccessToken")
+	defer span.End()
+
+	return s.validateToken(ctx, tokenString, TokenTypeAccess)
+}
+
+// validateToken validates a JWT token of the expected type, checks that
+// neither the token nor its session has been revoked, and returns the claims
+func (s *AuthService) validateToken(ctx context.Context, tokenString, tokenType string) (*Claims, error) {
 	token, err := jwt.ParseWithClaims(tokenString, &Claims{}, func(token *jwt.Token) (interface{}, error) {
-		return []byte(s.jwtSecret), nil
-	})
+		return SigningKey(s.jwtSecret, tokenType), nil
+	},
+		jwt.WithValidMethods([]string{jwt.SigningMethodHS256.Alg()}),
+		jwt.WithAudience(tokenAudiences[tokenType]),
+	)
 
 	if err != nil {
 		return nil, err
@@ -216,22 +441,71 @@ func (s *AuthService) ValidateToken(tokenString string) (*Claims, error) {
 		return nil, errors.New("invalid token claims")
 	}
 
+	if claims.TokenType != tokenType {
+		return nil, errors.New("wrong token type")
+	}
+
+	if claims.ID == "" || claims.SessionID == "" {
+		return nil, errors.New("invalid token claims")
+	}
+
+	revoked, err := s.sessionRepo.IsTokenRevoked(ctx, claims.ID)
+	if err != nil {
+		return nil, err
+	}
+	if revoked {
+		return nil, errors.New("token has been revoked")
+	}
+
+	session, err := s.sessionRepo.FindByID(ctx, claims.SessionID)
+	if err != nil {
+		return nil, err
+	}
+	if session.RevokedAt != nil {
+		return nil, errors.New("session has been revoked")
+	}
+
 	return claims, nil
 }
 
-// generateToken generates a JWT token for a user
-func (s *AuthService) generateToken(user *models.User, duration time.Duration) (string, error) {
+// PurgeExpiredSessions deletes sessions and revoked tokens that have expired
+func (s *AuthService) PurgeExpiredSessions(ctx context.Context) error {
+	ctx, span := tracer.Start(ctx, "AuthService.PurgeExpiredSessions")
+	defer span.End()
+
+	return s.sessionRepo.DeleteExpired(ctx, time.Now())
+}
+
+// generateToken generates a JWT token of the given type for a user within a session
+func (s *AuthService) generateToken(user *models.User, tokenType, sessionID, tokenID string, duration time.Duration) (string, error) {
 	claims := Claims{
-		UserID:   user.ID,
-		Username: user.Username,
-		Email:    user.Email,
+		UserID:    user.ID,
+		Username:  user.Username,
+		Email:     user.Email,
+		Role:      user.Role,
+		SessionID: sessionID,
+		TokenType: tokenType,
 		RegisteredClaims: jwt.RegisteredClaims{
+			ID:        tokenID,
+			Audience:  jwt.ClaimStrings{tokenAudiences[tokenType]},
 			ExpiresAt: jwt.NewNumericDate(time.Now().Add(duration)),
 			IssuedAt:  jwt.NewNumericDate(time.Now()),
 			NotBefore: jwt.NewNumericDate(time.Now()),
 		},
 	}
 
+	if user.CustomerID != nil {
+		claims.CustomerID = *user.CustomerID
+	}
+
 	token := jwt.NewWithClaims(jwt.SigningMethodHS256, claims)
-	return token.SignedString([]byte(s.jwtSecret))
+	return token.SignedString(SigningKey(s.jwtSecret, tokenType))
+}
+
+// SigningKey derives a separate HMAC key per token type from the configured
+// secret, so a token of one type can never verify as the other
+func SigningKey(secret, tokenType string) []byte {
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write([]byte(tokenType))
+	return mac.Sum(nil)
 }
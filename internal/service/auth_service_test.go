@@ -0,0 +1,74 @@
+package service_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/golang-jwt/jwt/v5"
+	"github.com/paudelanil/grpc-crud/internal/handler"
+	"github.com/paudelanil/grpc-crud/internal/service"
+	"github.com/paudelanil/grpc-crud/internal/service/authtest"
+	"github.com/paudelanil/grpc-crud/pb"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+func TestRefreshTokenRejectsOtherTokens(t *testing.T) {
+	authService, login := authtest.NewService(t)
+	refreshClaims := authtest.ClaimsOf(t, login.RefreshToken)
+	accessClaims := authtest.ClaimsOf(t, login.AccessToken)
+
+	tests := []struct {
+		name  string
+		token string
+	}{
+		{"access token", login.AccessToken},
+		{"access audience", authtest.Sign(t, service.TokenTypeRefresh, authtest.WithClaims(refreshClaims, jwt.MapClaims{"aud": []string{"grpc-crud/api"}}))},
+		{"access type", authtest.Sign(t, service.TokenTypeRefresh, authtest.WithClaims(refreshClaims, jwt.MapClaims{"typ": service.TokenTypeAccess}))},
+		{"signed with the access key", authtest.Sign(t, service.TokenTypeAccess, refreshClaims)},
+		{"access token with refresh audience", authtest.Sign(t, service.TokenTypeAccess, authtest.WithClaims(accessClaims, jwt.MapClaims{"aud": []string{"grpc-crud/token-refresh"}}))},
+		{"unknown audience", authtest.Sign(t, service.TokenTypeRefresh, authtest.WithClaims(refreshClaims, jwt.MapClaims{"aud": []string{"someone-else"}}))},
+	}
+
+	authHandler := handler.NewAuthHandler(authService)
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := authHandler.RefreshToken(context.Background(), &pb.TokenRequest{RefreshToken: tt.token})
+			if code := status.Code(err); code != codes.Unauthenticated {
+				t.Fatalf("RefreshToken code = %v, want %v (err: %v)", code, codes.Unauthenticated, err)
+			}
+		})
+	}
+
+	// The genuine refresh token still works after the rejected attempts
+	if _, err := authHandler.RefreshToken(context.Background(), &pb.TokenRequest{RefreshToken: login.RefreshToken}); err != nil {
+		t.Fatalf("RefreshToken with the refresh token: %v", err)
+	}
+}
+
+func TestValidateAccessTokenRejectsOtherTokens(t *testing.T) {
+	authService, login := authtest.NewService(t)
+	accessClaims := authtest.ClaimsOf(t, login.AccessToken)
+
+	tests := []struct {
+		name    string
+		token   string
+		wantErr bool
+	}{
+		{"access token", login.AccessToken, false},
+		{"refresh token", login.RefreshToken, true},
+		{"refresh audience", authtest.Sign(t, service.TokenTypeAccess, authtest.WithClaims(accessClaims, jwt.MapClaims{"aud": []string{"grpc-crud/token-refresh"}})), true},
+		{"unknown audience", authtest.Sign(t, service.TokenTypeAccess, authtest.WithClaims(accessClaims, jwt.MapClaims{"aud": []string{"someone-else"}})), true},
+		{"refresh type", authtest.Sign(t, service.TokenTypeAccess, authtest.WithClaims(accessClaims, jwt.MapClaims{"typ": service.TokenTypeRefresh})), true},
+		{"signed with the refresh key", authtest.Sign(t, service.TokenTypeRefresh, accessClaims), true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := authService.ValidateAccessToken(context.Background(), tt.token)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("ValidateAccessToken error = %v, want error: %v", err, tt.wantErr)
+			}
+		})
+	}
+}
@@ -0,0 +1,86 @@
+// Package authtest provides an AuthService with a logged-in user and
+// helpers to sign tokens with chosen claims, for tests
+package authtest
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/golang-jwt/jwt/v5"
+	"github.com/paudelanil/grpc-crud/internal/repository/repositorytest"
+	"github.com/paudelanil/grpc-crud/internal/service"
+	"github.com/paudelanil/grpc-crud/models"
+	"github.com/paudelanil/grpc-crud/pb"
+	"golang.org/x/crypto/bcrypt"
+)
+
+// Settings of the service and its one user
+const (
+	Secret   = "test-secret-that-is-long-enough-for-hs256"
+	UserID   = "user-1"
+	Username = "alice"
+	Password = "correct horse battery"
+	Role     = models.RoleCustomer
+)
+
+// NewService returns an AuthService with one active customer user and a
+// login of that user
+func NewService(t testing.TB) (service.IAuthService, *pb.UserLoginResponse) {
+	t.Helper()
+
+	hash, err := bcrypt.GenerateFromPassword([]byte(Password), bcrypt.MinCost)
+	if err != nil {
+		t.Fatal(err)
+	}
+	users := repositorytest.NewUserRepository(&models.User{
+		ID:       UserID,
+		Username: Username,
+		Email:    "alice@example.com",
+		Password: string(hash),
+		Role:     Role,
+		IsActive: true,
+	})
+
+	authService := service.NewAuthService(users, nil, repositorytest.NewSessionRepository(), Secret, 15*time.Minute, time.Hour, service.NopEventRecorder{})
+	login, err := authService.Login(context.Background(), &pb.UserLoginRequest{Username: Username, Password: Password})
+	if err != nil {
+		t.Fatalf("Login: %v", err)
+	}
+	return authService, login
+}
+
+// Sign signs claims with the key the service derives for keyType, so only
+// the claims under test are wrong
+func Sign(t testing.TB, keyType string, claims jwt.MapClaims) string {
+	t.Helper()
+
+	token, err := jwt.NewWithClaims(jwt.SigningMethodHS256, claims).SignedString(service.SigningKey(Secret, keyType))
+	if err != nil {
+		t.Fatal(err)
+	}
+	return token
+}
+
+// ClaimsOf returns the claims of a token issued by the service
+func ClaimsOf(t testing.TB, token string) jwt.MapClaims {
+	t.Helper()
+
+	claims := jwt.MapClaims{}
+	if _, _, err := jwt.NewParser().ParseUnverified(token, claims); err != nil {
+		t.Fatal(err)
+	}
+	return claims
+}
+
+// WithClaims copies claims, overriding some of them
+func WithClaims(claims jwt.MapClaims, overrides jwt.MapClaims) jwt.MapClaims {
+	copied := jwt.MapClaims{}
+	for k, v := range claims {
+		copied[k] = v
+	}
+	for k, v := range overrides {
+		copied[k] = v
+	}
+	return copied
+}
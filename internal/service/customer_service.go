@@ -2,10 +2,11 @@ package service
 
 import (
 	"context"
-	"errors"
+	"fmt"
 	"time"
 
 	"github.com/google/uuid"
+	"github.com/paudelanil/grpc-crud/internal/pagetoken"
 	"github.com/paudelanil/grpc-crud/internal/repository"
 	"github.com/paudelanil/grpc-crud/models"
 	"github.com/paudelanil/grpc-crud/pb"
@@ -23,46 +24,40 @@ type ICustomerService interface {
 // CustomerService implements ICustomerService interface
 type CustomerService struct {
 	customerRepo repository.ICustomerRepository
+	pageTokens   *pagetoken.Signer
+	events       IEventRecorder
 }
 
 // NewCustomerService creates a new instance of CustomerService
-func NewCustomerService(customerRepo repository.ICustomerRepository) ICustomerService {
+func NewCustomerService(customerRepo repository.ICustomerRepository, pageTokens *pagetoken.Signer, events IEventRecorder) ICustomerService {
 	return &CustomerService{
 		customerRepo: customerRepo,
+		pageTokens:   pageTokens,
+		events:       events,
 	}
 }
 
 // CreateCustomer creates a new customer
 func (s *CustomerService) CreateCustomer(ctx context.Context, req *pb.CreateCustomerRequest) (*pb.CreateCustomerResponse, error) {
+	ctx, span := tracer.Start(ctx, "CustomerService.CreateCustomer")
+	defer span.End()
+
 	// Validate input
-	if req.FirstName == "" || req.LastName == "" {
-		return nil, errors.New("first name and last name are required")
+	var violations []FieldViolation
+	if req.FirstName == "" {
+		violations = append(violations, FieldViolation{Field: "first_name", Description: "first name is required"})
+	}
+	if req.LastName == "" {
+		violations = append(violations, FieldViolation{Field: "last_name", Description: "last name is required"})
 	}
-
 	if req.Email == "" {
-		return nil, errors.New("email is required")
+		violations = append(violations, FieldViolation{Field: "email", Description: "email is required"})
 	}
-
 	if req.PhoneNumber == "" {
-		return nil, errors.New("phone number is required")
-	}
-
-	// Check if email is already taken
-	emailTaken, err := s.customerRepo.IsEmailTaken(ctx, req.Email)
-	if err != nil {
-		return nil, err
+		violations = append(violations, FieldViolation{Field: "phone_number", Description: "phone number is required"})
 	}
-	if emailTaken {
-		return nil, errors.New("email is already in use")
-	}
-
-	// Check if phone is already taken
-	phoneTaken, err := s.customerRepo.IsPhoneTaken(ctx, req.PhoneNumber)
-	if err != nil {
-		return nil, err
-	}
-	if phoneTaken {
-		return nil, errors.New("phone number is already in use")
+	if len(violations) > 0 {
+		return nil, &ValidationError{Violations: violations}
 	}
 
 	// Create customer model
@@ -77,10 +72,11 @@ func (s *CustomerService) CreateCustomer(ctx context.Context, req *pb.CreateCust
 		UpdatedAt: time.Now(),
 	}
 
-	// Save to database
+	// Save to database; the unique indexes reject a taken email or phone number
 	if err := s.customerRepo.Create(ctx, customer); err != nil {
-		return nil, errors.New("failed to create customer")
+		return nil, fmt.Errorf("failed to create customer: %w", err)
 	}
+	s.events.CustomerCreated()
 
 	return &pb.CreateCustomerResponse{
 		CustomerId: customer.ID,
@@ -90,8 +86,11 @@ func (s *CustomerService) CreateCustomer(ctx context.Context, req *pb.CreateCust
 
 // GetCustomer retrieves a customer by ID
 func (s *CustomerService) GetCustomer(ctx context.Context, req *pb.GetCustomerRequest) (*pb.GetCustomerResponse, error) {
+	ctx, span := tracer.Start(ctx, "CustomerService.GetCustomer")
+	defer span.End()
+
 	if req.CustomerId == "" {
-		return nil, errors.New("customer ID is required")
+		return nil, invalidField("customer_id", "customer ID is required")
 	}
 
 	customer, err := s.customerRepo.FindByID(ctx, req.CustomerId)
@@ -113,8 +112,11 @@ func (s *CustomerService) GetCustomer(ctx context.Context, req *pb.GetCustomerRe
 
 // UpdateCustomer updates an existing customer
 func (s *CustomerService) UpdateCustomer(ctx context.Context, req *pb.UpdateCustomerRequest) (*pb.UpdateCustomerResponse, error) {
+	ctx, span := tracer.Start(ctx, "CustomerService.UpdateCustomer")
+	defer span.End()
+
 	if req.CustomerId == "" {
-		return nil, errors.New("customer ID is required")
+		return nil, invalidField("customer_id", "customer ID is required")
 	}
 
 	// Find existing customer
@@ -143,7 +145,7 @@ func (s *CustomerService) UpdateCustomer(ctx context.Context, req *pb.UpdateCust
 
 	// Save changes
 	if err := s.customerRepo.Update(ctx, customer); err != nil {
-		return nil, errors.New("failed to update customer")
+		return nil, fmt.Errorf("failed to update customer: %w", err)
 	}
 
 	return &pb.UpdateCustomerResponse{
@@ -163,8 +165,11 @@ func (s *CustomerService) UpdateCustomer(ctx context.Context, req *pb.UpdateCust
 
 // DeleteCustomer deletes a customer by ID
 func (s *CustomerService) DeleteCustomer(ctx context.Context, req *pb.DeleteCustomerRequest) (*pb.DeleteCustomerResponse, error) {
+	ctx, span := tracer.Start(ctx, "CustomerService.DeleteCustomer")
+	defer span.End()
+
 	if req.CustomerId == "" {
-		return nil, errors.New("customer ID is required")
+		return nil, invalidField("customer_id", "customer ID is required")
 	}
 
 	if err := s.customerRepo.Delete(ctx, req.CustomerId); err != nil {
@@ -176,25 +181,28 @@ func (s *CustomerService) DeleteCustomer(ctx context.Context, req *pb.DeleteCust
 	}, nil
 }
 
-// ListCustomers lists all customers with pagination
+// ListCustomers lists all customers, oldest first, one page at a time
 func (s *CustomerService) ListCustomers(ctx context.Context, req *pb.ListCustomerRequest) (*pb.ListCustomerResponse, error) {
-	// Set default pagination values
-	pageSize := int(req.PageSize)
-	if pageSize <= 0 {
-		pageSize = 10
-	}
+	ctx, span := tracer.Start(ctx, "CustomerService.ListCustomers")
+	defer span.End()
 
-	pageNumber := int(req.PageNumber)
-	if pageNumber <= 0 {
-		pageNumber = 1
+	page, err := parsePage("customers", req.PageNumber, req.PageSize, req.PageToken, req.TotalCountMode, s.pageTokens)
+	if err != nil {
+		return nil, err
 	}
 
-	offset := (pageNumber - 1) * pageSize
-
 	// Fetch customers
-	customers, err := s.customerRepo.FindAll(ctx, pageSize, offset)
+	customers, err := s.customerRepo.FindAll(ctx, page.query)
+	if err != nil {
+		return nil, fmt.Errorf("failed to retrieve customers: %w", err)
+	}
+	customers, nextPageToken := trimPage(page, customers, s.pageTokens, func(c *models.Customer) repository.Cursor {
+		return repository.Cursor{CreatedAt: c.CreatedAt, ID: c.ID}
+	})
+
+	total, estimated, err := totalCount(ctx, req.TotalCountMode, s.customerRepo.Count, s.customerRepo.EstimateCount)
 	if err != nil {
-		return nil, errors.New("failed to retrieve customers")
+		return nil, fmt.Errorf("failed to count customers: %w", err)
 	}
 
 	// Convert to response format
@@ -213,6 +221,9 @@ func (s *CustomerService) ListCustomers(ctx context.Context, req *pb.ListCustome
 	}
 
 	return &pb.ListCustomerResponse{
-		Customers: customerResponses,
+		Customers:           customerResponses,
+		TotalCount:          total,
+		NextPageToken:       nextPageToken,
+		TotalCountEstimated: estimated,
 	}, nil
 }
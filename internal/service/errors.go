@@ -0,0 +1,92 @@
+package service
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/paudelanil/grpc-crud/internal/repository"
+)
+
+// Error kinds returned by the services; match them with errors.Is. The
+// repository kinds are passed through unchanged.
+var (
+	// ErrNotFound is returned when a requested record does not exist
+	ErrNotFound = repository.ErrNotFound
+	// ErrAlreadyExists is returned when a unique field is already in use
+	ErrAlreadyExists = repository.ErrAlreadyExists
+	// ErrConflict is returned when the current state does not allow the request
+	ErrConflict = repository.ErrConflict
+	// ErrValidation is matched by every ValidationError
+	ErrValidation = errors.New("invalid request")
+	// ErrUnauthenticated is matched by every AuthError
+	ErrUnauthenticated = errors.New("unauthenticated")
+	// ErrPermissionDenied is matched by every PermissionError
+	ErrPermissionDenied = errors.New("permission denied")
+)
+
+// FieldViolation describes one invalid request field
+type FieldViolation struct {
+	Field       string // request field path, e.g. amount.currency
+	Description string
+}
+
+// ValidationError reports invalid request fields
+type ValidationError struct {
+	Violations []FieldViolation
+}
+
+func (e *ValidationError) Error() string {
+	descriptions := make([]string, len(e.Violations))
+	for i, v := range e.Violations {
+		descriptions[i] = v.Description
+	}
+	return strings.Join(descriptions, "; ")
+}
+
+func (e *ValidationError) Is(target error) bool {
+	return target == ErrValidation
+}
+
+// invalidField returns a ValidationError for a single field
+func invalidField(field, description string) error {
+	return &ValidationError{Violations: []FieldViolation{{Field: field, Description: description}}}
+}
+
+// afterCommit wraps an error raised once a write has been committed. The
+// cause is flattened so that, for example, a NotFound from reloading a row
+// is not mistaken for a request that failed before changing anything and
+// is safe to retry.
+func afterCommit(what string, err error) error {
+	return fmt.Errorf("%s after commit: %v", what, err)
+}
+
+// AuthError reports failed authentication. Reason is a stable,
+// machine-readable code such as INVALID_CREDENTIALS.
+type AuthError struct {
+	Reason  string
+	Message string
+}
+
+func (e *AuthError) Error() string {
+	return e.Message
+}
+
+func (e *AuthError) Is(target error) bool {
+	return target == ErrUnauthenticated
+}
+
+// PermissionError reports an authenticated caller acting on something that
+// is not theirs. Reason is a stable, machine-readable code.
+type PermissionError struct {
+	Reason  string
+	Message string
+}
+
+func (e *PermissionError) Error() string {
+	return e.Message
+}
+
+func (e *PermissionError) Is(target error) bool {
+	return target == ErrPermissionDenied
+}
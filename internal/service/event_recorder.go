@@ -0,0 +1,15 @@
+package service
+
+// IEventRecorder receives business events for monitoring
+type IEventRecorder interface {
+	CustomerCreated()
+	AccountOpened()
+	LoginFailed(reason string)
+}
+
+// NopEventRecorder discards all events
+type NopEventRecorder struct{}
+
+func (NopEventRecorder) CustomerCreated()   {}
+func (NopEventRecorder) AccountOpened()     {}
+func (NopEventRecorder) LoginFailed(string) {}
@@ -0,0 +1,184 @@
+package service
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/paudelanil/grpc-crud/internal/repository"
+	"github.com/paudelanil/grpc-crud/models"
+	spb "google.golang.org/genproto/googleapis/rpc/status"
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/types/known/anypb"
+)
+
+var (
+	// ErrIdempotencyKeyReused is returned when a key is sent again with a different request
+	ErrIdempotencyKeyReused = errors.New("idempotency key was already used for a different request")
+	// ErrIdempotencyInProgress is returned when the original request for a key has not finished yet
+	ErrIdempotencyInProgress = errors.New("a request with this idempotency key is already in progress")
+)
+
+// ReplayedFailureError is returned by Begin when the request first sent
+// with the key failed in a way that must not be retried blindly. Status is
+// the error the first request returned.
+type ReplayedFailureError struct {
+	Status *spb.Status
+}
+
+func (e *ReplayedFailureError) Error() string {
+	return e.Status.GetMessage()
+}
+
+// IIdempotencyService defines the interface for idempotent request handling
+type IIdempotencyService interface {
+	Begin(ctx context.Context, userID, key, method string, req proto.Message) (proto.Message, string, error)
+	Complete(ctx context.Context, recordID string, resp proto.Message) error
+	Fail(ctx context.Context, recordID string, failure *spb.Status) error
+	Abandon(ctx context.Context, recordID string) error
+	PurgeExpired(ctx context.Context) (int64, error)
+}
+
+// IdempotencyService implements IIdempotencyService interface
+type IdempotencyService struct {
+	idempotencyRepo repository.IIdempotencyRepository
+	ttl             time.Duration
+	lease           time.Duration
+}
+
+// NewIdempotencyService creates a new instance of IdempotencyService.
+// Responses are replayed for ttl after the request completes. A request
+// that has not completed within lease, for example because the server
+// crashed, loses its key to the next request that uses it, so lease must
+// be longer than any request may run.
+func NewIdempotencyService(idempotencyRepo repository.IIdempotencyRepository, ttl, lease time.Duration) IIdempotencyService {
+	return &IdempotencyService{
+		idempotencyRepo: idempotencyRepo,
+		ttl:             ttl,
+		lease:           lease,
+	}
+}
+
+// Begin reserves an idempotency key for a request. When the key already
+// completed for the same request the stored response, or a
+// ReplayedFailureError, is returned and the caller must not run the request
+// again; otherwise the returned record ID must be passed to Complete, Fail
+// or Abandon.
+func (s *IdempotencyService) Begin(ctx context.Context, userID, key, method string, req proto.Message) (proto.Message, string, error) {
+	if key == "" {
+		return nil, "", errors.New("idempotency key is required")
+	}
+
+	hash, err := fingerprint(method, req)
+	if err != nil {
+		return nil, "", err
+	}
+
+	now := time.Now()
+	record := &models.IdempotencyRecord{
+		ID:          uuid.New().String(),
+		UserID:      userID,
+		Key:         key,
+		Method:      method,
+		RequestHash: hash,
+		ExpiresAt:   now.Add(s.lease),
+		CreatedAt:   now,
+	}
+
+	created, err := s.idempotencyRepo.Create(ctx, record)
+	if err != nil {
+		return nil, "", err
+	}
+	if created {
+		return nil, record.ID, nil
+	}
+
+	existing, err := s.idempotencyRepo.FindByKey(ctx, userID, key)
+	if err != nil {
+		return nil, "", err
+	}
+
+	// An expired key, or one whose lease ran out, is free to be used again
+	if existing.ExpiresAt.Before(now) {
+		if err := s.idempotencyRepo.Delete(ctx, existing.ID); err != nil {
+			return nil, "", err
+		}
+		return s.Begin(ctx, userID, key, method, req)
+	}
+
+	if existing.Method != method || existing.RequestHash != hash {
+		return nil, "", ErrIdempotencyKeyReused
+	}
+
+	if existing.CompletedAt == nil {
+		return nil, "", ErrIdempotencyInProgress
+	}
+
+	if len(existing.Failure) > 0 {
+		var failure spb.Status
+		if err := proto.Unmarshal(existing.Failure, &failure); err != nil {
+			return nil, "", err
+		}
+		return nil, "", &ReplayedFailureError{Status: &failure}
+	}
+
+	var stored anypb.Any
+	if err := proto.Unmarshal(existing.Response, &stored); err != nil {
+		return nil, "", err
+	}
+	resp, err := stored.UnmarshalNew()
+	if err != nil {
+		return nil, "", err
+	}
+	return resp, "", nil
+}
+
+// Complete stores the response of a request started with Begin
+func (s *IdempotencyService) Complete(ctx context.Context, recordID string, resp proto.Message) error {
+	stored, err := anypb.New(resp)
+	if err != nil {
+		return err
+	}
+	data, err := proto.Marshal(stored)
+	if err != nil {
+		return err
+	}
+	return s.idempotencyRepo.Complete(ctx, recordID, data, time.Now().Add(s.ttl))
+}
+
+// Fail stores the error of a request started with Begin, to be returned
+// to retries instead of running the request again
+func (s *IdempotencyService) Fail(ctx context.Context, recordID string, failure *spb.Status) error {
+	data, err := proto.Marshal(failure)
+	if err != nil {
+		return err
+	}
+	return s.idempotencyRepo.Fail(ctx, recordID, data, time.Now().Add(s.ttl))
+}
+
+// Abandon releases the key of a request that failed before changing
+// anything, so the client can retry it
+func (s *IdempotencyService) Abandon(ctx context.Context, recordID string) error {
+	return s.idempotencyRepo.Delete(ctx, recordID)
+}
+
+// PurgeExpired deletes idempotency records past their expiry
+func (s *IdempotencyService) PurgeExpired(ctx context.Context) (int64, error) {
+	return s.idempotencyRepo.DeleteExpired(ctx, time.Now())
+}
+
+// fingerprint hashes the method and the deterministic encoding of the request
+func fingerprint(method string, req proto.Message) (string, error) {
+	data, err := proto.MarshalOptions{Deterministic: true}.Marshal(req)
+	if err != nil {
+		return "", err
+	}
+	sum := sha256.New()
+	sum.Write([]byte(method))
+	sum.Write([]byte{0})
+	sum.Write(data)
+	return hex.EncodeToString(sum.Sum(nil)), nil
+}
@@ -0,0 +1,151 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/paudelanil/grpc-crud/internal/money"
+	"github.com/paudelanil/grpc-crud/internal/repository"
+	"github.com/paudelanil/grpc-crud/models"
+)
+
+// ILedgerService defines the interface for double-entry ledger operations
+type ILedgerService interface {
+	PostEntry(ctx context.Context, entry *models.JournalEntry, guard repository.AccountGuard) error
+	OpenAccount(ctx context.Context, account *models.Account, entry *models.JournalEntry) error
+	GetEntry(ctx context.Context, id string) (*models.JournalEntry, error)
+	GetAccountPostings(ctx context.Context, accountID string, limit, offset int) ([]*models.Posting, error)
+	GetLedgerBalance(ctx context.Context, accountID string) (int64, error)
+	RebuildBalance(ctx context.Context, accountID string) (int64, error)
+}
+
+// LedgerService implements ILedgerService interface
+type LedgerService struct {
+	ledgerRepo repository.ILedgerRepository
+}
+
+// NewLedgerService creates a new instance of LedgerService
+func NewLedgerService(ledgerRepo repository.ILedgerRepository) ILedgerService {
+	return &LedgerService{
+		ledgerRepo: ledgerRepo,
+	}
+}
+
+// PostEntry validates that a journal entry is balanced and records it. The
+// guard, if any, runs against the locked accounts inside the transaction.
+func (s *LedgerService) PostEntry(ctx context.Context, entry *models.JournalEntry, guard repository.AccountGuard) error {
+	if err := prepareEntry(entry); err != nil {
+		return err
+	}
+
+	return s.ledgerRepo.CreateEntry(ctx, entry, guard)
+}
+
+// OpenAccount validates the journal entry funding a new account and records
+// it together with the account
+func (s *LedgerService) OpenAccount(ctx context.Context, account *models.Account, entry *models.JournalEntry) error {
+	if account == nil {
+		return errors.New("account is required")
+	}
+
+	if err := prepareEntry(entry); err != nil {
+		return err
+	}
+
+	return s.ledgerRepo.OpenAccount(ctx, account, entry)
+}
+
+// prepareEntry checks that a journal entry is balanced and assigns the IDs
+// and timestamps of the entry and its postings
+func prepareEntry(entry *models.JournalEntry) error {
+	if entry == nil {
+		return errors.New("journal entry is required")
+	}
+
+	if entry.EntryType == "" {
+		return errors.New("entry type is required")
+	}
+
+	if !money.IsSupported(entry.Currency) {
+		return money.ErrUnsupportedCurrency
+	}
+
+	if len(entry.Postings) < 2 {
+		return errors.New("journal entry needs at least two postings")
+	}
+
+	// Every posting must move money and the entry as a whole must net to zero
+	var total int64
+	for _, posting := range entry.Postings {
+		if posting.AccountID == "" {
+			return errors.New("posting account ID is required")
+		}
+		if posting.Amount == 0 {
+			return errors.New("posting amount must not be zero")
+		}
+		total += posting.Amount
+	}
+	if total != 0 {
+		return errors.New("journal entry is not balanced")
+	}
+
+	now := time.Now()
+	entry.ID = uuid.New().String()
+	if entry.PostedAt.IsZero() {
+		entry.PostedAt = now
+	}
+	entry.CreatedAt = now
+	for i := range entry.Postings {
+		entry.Postings[i].ID = uuid.New().String()
+		entry.Postings[i].EntryID = entry.ID
+		entry.Postings[i].CreatedAt = now
+	}
+
+	return nil
+}
+
+// GetEntry retrieves a journal entry with its postings
+func (s *LedgerService) GetEntry(ctx context.Context, id string) (*models.JournalEntry, error) {
+	if id == "" {
+		return nil, errors.New("entry ID is required")
+	}
+
+	return s.ledgerRepo.FindEntryByID(ctx, id)
+}
+
+// GetAccountPostings lists the postings of an account with pagination
+func (s *LedgerService) GetAccountPostings(ctx context.Context, accountID string, limit, offset int) ([]*models.Posting, error) {
+	if accountID == "" {
+		return nil, errors.New("account ID is required")
+	}
+
+	if limit <= 0 {
+		limit = 10
+	}
+
+	return s.ledgerRepo.FindPostingsByAccountID(ctx, accountID, limit, offset)
+}
+
+// GetLedgerBalance returns the balance of an account computed from its postings
+func (s *LedgerService) GetLedgerBalance(ctx context.Context, accountID string) (int64, error) {
+	if accountID == "" {
+		return 0, errors.New("account ID is required")
+	}
+
+	return s.ledgerRepo.SumPostingsByAccountID(ctx, accountID)
+}
+
+// RebuildBalance replaces the cached balance of an account with the sum of its postings
+func (s *LedgerService) RebuildBalance(ctx context.Context, accountID string) (int64, error) {
+	if accountID == "" {
+		return 0, errors.New("account ID is required")
+	}
+
+	if models.IsInternalAccount(accountID) {
+		return 0, errors.New("internal accounts have no cached balance")
+	}
+
+	return s.ledgerRepo.RebuildBalance(ctx, accountID)
+}
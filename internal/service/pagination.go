@@ -0,0 +1,93 @@
+package service
+
+import (
+	"context"
+	"math"
+
+	"github.com/paudelanil/grpc-crud/internal/pagetoken"
+	"github.com/paudelanil/grpc-crud/internal/repository"
+)
+
+// defaultPageSize is used when a list request names no page size
+const defaultPageSize = 10
+
+// Total count modes of list requests; no mode means exact
+const (
+	countExact     = "exact"
+	countEstimated = "estimated"
+	countNone      = "none"
+)
+
+// listPage is the paging of one list request
+type listPage struct {
+	scope string // list the page tokens belong to
+	size  int
+	query repository.PageQuery
+}
+
+// parsePage reads the paging fields of a list request. The deprecated page
+// number is served with OFFSET and cannot be combined with a page token.
+// One row more than the page size is queried, to learn whether a next page
+// exists.
+func parsePage(scope string, pageNumber, pageSize int32, pageToken, countMode string, tokens *pagetoken.Signer) (*listPage, error) {
+	switch countMode {
+	case "", countExact, countEstimated, countNone:
+	default:
+		return nil, invalidField("total_count_mode", "total count mode must be exact, estimated or none")
+	}
+
+	size := int(pageSize)
+	if size <= 0 {
+		size = defaultPageSize
+	}
+	page := &listPage{scope: scope, size: size, query: repository.PageQuery{Limit: size + 1}}
+
+	switch {
+	case pageToken != "" && pageNumber > 1:
+		return nil, invalidField("page_token", "give either a page token or a page number, not both")
+	case pageToken != "":
+		cursor, err := tokens.Decode(scope, pageToken)
+		if err != nil {
+			return nil, invalidField("page_token", "page token is not valid")
+		}
+		page.query.After = &repository.Cursor{CreatedAt: cursor.Time, ID: cursor.ID}
+	case pageNumber > 1:
+		page.query.Offset = (int(pageNumber) - 1) * size
+	}
+	return page, nil
+}
+
+// trimPage cuts the extra row off a page and returns the token of the next
+// page, or "" on the last page
+func trimPage[T any](page *listPage, rows []T, tokens *pagetoken.Signer, cursor func(T) repository.Cursor) ([]T, string) {
+	if len(rows) <= page.size {
+		return rows, ""
+	}
+	rows = rows[:page.size]
+	last := cursor(rows[len(rows)-1])
+	return rows, tokens.Encode(page.scope, pagetoken.Cursor{Time: last.CreatedAt, ID: last.ID})
+}
+
+// totalCount counts the rows of a list as the request's total count mode,
+// already checked by parsePage, asks; the none mode skips counting and
+// returns 0. estimate may be nil when the list has no cheap estimate; the
+// exact count is used instead. It returns the count and whether it is an
+// estimate.
+func totalCount(ctx context.Context, mode string, exact, estimate func(context.Context) (int64, error)) (int32, bool, error) {
+	var count int64
+	var err error
+	estimated := false
+	switch {
+	case mode == countNone:
+		return 0, false, nil
+	case mode == countEstimated && estimate != nil:
+		count, err = estimate(ctx)
+		estimated = true
+	default:
+		count, err = exact(ctx)
+	}
+	if err != nil {
+		return 0, false, err
+	}
+	return int32(min(count, math.MaxInt32)), estimated, nil
+}
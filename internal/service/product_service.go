@@ -0,0 +1,210 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/paudelanil/grpc-crud/internal/accountnumber"
+	"github.com/paudelanil/grpc-crud/internal/money"
+	"github.com/paudelanil/grpc-crud/internal/repository"
+	"github.com/paudelanil/grpc-crud/models"
+	"github.com/paudelanil/grpc-crud/pb"
+)
+
+// IProductService defines the interface for account product catalog operations
+type IProductService interface {
+	CreateProduct(ctx context.Context, req *pb.CreateProductRequest) (*pb.CreateProductResponse, error)
+	GetProduct(ctx context.Context, req *pb.GetProductRequest) (*pb.AccountProduct, error)
+	UpdateProduct(ctx context.Context, req *pb.UpdateProductRequest) (*pb.UpdateProductResponse, error)
+	ListProducts(ctx context.Context, req *pb.ListProductsRequest) (*pb.ListProductsResponse, error)
+}
+
+// ProductService implements IProductService interface
+type ProductService struct {
+	productRepo repository.IProductRepository
+}
+
+// NewProductService creates a new instance of ProductService
+func NewProductService(productRepo repository.IProductRepository) IProductService {
+	return &ProductService{
+		productRepo: productRepo,
+	}
+}
+
+// CreateProduct adds a product to the catalog
+func (s *ProductService) CreateProduct(ctx context.Context, req *pb.CreateProductRequest) (*pb.CreateProductResponse, error) {
+	ctx, span := tracer.Start(ctx, "ProductService.CreateProduct")
+	defer span.End()
+
+	product, err := toProductModel(req.Product)
+	if err != nil {
+		return nil, err
+	}
+	product.CreatedAt = product.UpdatedAt
+
+	if err := s.productRepo.Create(ctx, product); err != nil {
+		return nil, fmt.Errorf("failed to create product: %w", err)
+	}
+
+	return &pb.CreateProductResponse{
+		Product: toProductResponse(product),
+		Message: "Product created successfully",
+	}, nil
+}
+
+// GetProduct retrieves a product by code
+func (s *ProductService) GetProduct(ctx context.Context, req *pb.GetProductRequest) (*pb.AccountProduct, error) {
+	ctx, span := tracer.Start(ctx, "ProductService.GetProduct")
+	defer span.End()
+
+	if req.Code == "" {
+		return nil, invalidField("code", "product code is required")
+	}
+
+	product, err := s.productRepo.FindByCode(ctx, req.Code)
+	if err != nil {
+		return nil, err
+	}
+
+	return toProductResponse(product), nil
+}
+
+// UpdateProduct replaces the settings of a product. Existing accounts keep
+// their currency even if the product no longer offers it.
+func (s *ProductService) UpdateProduct(ctx context.Context, req *pb.UpdateProductRequest) (*pb.UpdateProductResponse, error) {
+	ctx, span := tracer.Start(ctx, "ProductService.UpdateProduct")
+	defer span.End()
+
+	product, err := toProductModel(req.Product)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := s.productRepo.Update(ctx, product); err != nil {
+		return nil, fmt.Errorf("failed to update product: %w", err)
+	}
+
+	// Reload for the creation time
+	updated, err := s.productRepo.FindByCode(ctx, product.Code)
+	if err != nil {
+		return nil, afterCommit("failed to reload product", err)
+	}
+
+	return &pb.UpdateProductResponse{
+		Product: toProductResponse(updated),
+		Message: "Product updated successfully",
+	}, nil
+}
+
+// ListProducts lists the catalog
+func (s *ProductService) ListProducts(ctx context.Context, req *pb.ListProductsRequest) (*pb.ListProductsResponse, error) {
+	ctx, span := tracer.Start(ctx, "ProductService.ListProducts")
+	defer span.End()
+
+	products, err := s.productRepo.FindAll(ctx, req.IncludeInactive)
+	if err != nil {
+		return nil, fmt.Errorf("failed to retrieve products: %w", err)
+	}
+
+	var productResponses []*pb.AccountProduct
+	for _, product := range products {
+		productResponses = append(productResponses, toProductResponse(product))
+	}
+
+	return &pb.ListProductsResponse{
+		Products: productResponses,
+	}, nil
+}
+
+// toProductModel checks a product message and converts it to the model
+func toProductModel(product *pb.AccountProduct) (*models.AccountProduct, error) {
+	if product == nil {
+		return nil, invalidField("product", "product is required")
+	}
+
+	var violations []FieldViolation
+	if product.Code == "" {
+		violations = append(violations, FieldViolation{Field: "product.code", Description: "product code is required"})
+	}
+	if product.Name == "" {
+		violations = append(violations, FieldViolation{Field: "product.name", Description: "product name is required"})
+	}
+	if err := accountnumber.ValidatePrefix(product.NumberPrefix); err != nil {
+		violations = append(violations, FieldViolation{Field: "product.number_prefix", Description: err.Error()})
+	}
+	if product.MaxAccountsPerCustomer < 0 {
+		violations = append(violations, FieldViolation{Field: "product.max_accounts_per_customer", Description: "max accounts per customer must not be negative"})
+	}
+	if len(product.Currencies) == 0 {
+		violations = append(violations, FieldViolation{Field: "product.currencies", Description: "at least one currency is required"})
+	}
+
+	now := time.Now()
+	model := &models.AccountProduct{
+		Code:                   product.Code,
+		Name:                   product.Name,
+		Description:            product.Description,
+		AllowWithdrawals:       product.AllowWithdrawals,
+		MaxAccountsPerCustomer: int(product.MaxAccountsPerCustomer),
+		Active:                 product.Active,
+		NumberPrefix:           product.NumberPrefix,
+		UpdatedAt:              now,
+	}
+
+	for i, c := range product.Currencies {
+		field := fmt.Sprintf("product.currencies[%d]", i)
+		currency, err := money.Lookup(c.Currency)
+		if err != nil {
+			violations = append(violations, FieldViolation{Field: field + ".currency", Description: err.Error()})
+			continue
+		}
+		if _, dup := model.Currency(currency.Code); dup {
+			violations = append(violations, FieldViolation{Field: field + ".currency", Description: fmt.Sprintf("currency %s is listed twice", currency.Code)})
+			continue
+		}
+		if c.MinOpeningBalance < 0 {
+			violations = append(violations, FieldViolation{Field: field + ".min_opening_balance", Description: "minimum opening balance must not be negative"})
+		}
+		model.Currencies = append(model.Currencies, models.ProductCurrency{
+			ProductCode:       product.Code,
+			Currency:          currency.Code,
+			MinOpeningBalance: c.MinOpeningBalance,
+		})
+	}
+
+	if len(violations) > 0 {
+		return nil, &ValidationError{Violations: violations}
+	}
+	return model, nil
+}
+
+// toProductResponse converts a product model to its message
+func toProductResponse(product *models.AccountProduct) *pb.AccountProduct {
+	currencies := make([]*pb.ProductCurrency, len(product.Currencies))
+	for i, c := range product.Currencies {
+		currencies[i] = &pb.ProductCurrency{
+			Currency:          c.Currency,
+			MinOpeningBalance: c.MinOpeningBalance,
+		}
+	}
+
+	return &pb.AccountProduct{
+		Code:                   product.Code,
+		Name:                   product.Name,
+		Description:            product.Description,
+		Currencies:             currencies,
+		AllowWithdrawals:       product.AllowWithdrawals,
+		MaxAccountsPerCustomer: int32(product.MaxAccountsPerCustomer),
+		Active:                 product.Active,
+		NumberPrefix:           product.NumberPrefix,
+		CreatedAt:              product.CreatedAt.Format(time.RFC3339),
+		UpdatedAt:              product.UpdatedAt.Format(time.RFC3339),
+	}
+}
+
+// productName returns the product's name for messages, e.g. "fixed deposit"
+func productName(product *models.AccountProduct) string {
+	return strings.ToLower(product.Name)
+}
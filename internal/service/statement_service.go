@@ -0,0 +1,339 @@
+package service
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/paudelanil/grpc-crud/internal/pagetoken"
+	"github.com/paudelanil/grpc-crud/internal/repository"
+	"github.com/paudelanil/grpc-crud/internal/statementexport"
+	"github.com/paudelanil/grpc-crud/models"
+	"github.com/paudelanil/grpc-crud/pb"
+)
+
+// Statement limits
+const (
+	defaultStatementPageSize = 50
+	statementChunkSize       = 500 // lines per streamed chunk
+	statementDateLayout      = "2006-01-02"
+
+	// An export is rendered in memory, so its size is capped. Longer
+	// statements are read with StreamStatement.
+	maxExportDays  = 366
+	maxExportLines = 10000
+)
+
+// ErrStatementTooLarge is returned when a statement period has too many
+// lines to export as a file
+var ErrStatementTooLarge = &repository.ConflictError{Reason: "STATEMENT_TOO_LARGE", Message: fmt.Sprintf("statement has more than %d lines; export a shorter period", maxExportLines)}
+
+// IStatementService defines the interface for account statement operations.
+// A non-empty customerID restricts the statement to that customer's
+// accounts; other accounts are reported as not found.
+type IStatementService interface {
+	GetStatement(ctx context.Context, customerID string, req *pb.GetStatementRequest) (*pb.GetStatementResponse, error)
+	StreamStatement(ctx context.Context, customerID string, req *pb.StreamStatementRequest, send func(*pb.StatementChunk) error) error
+	ExportStatement(ctx context.Context, customerID string, req *pb.ExportStatementRequest) (*StatementFile, error)
+}
+
+// StatementFile is a rendered statement
+type StatementFile struct {
+	ContentType string
+	Filename    string
+	Data        []byte
+}
+
+// StatementService implements IStatementService interface
+type StatementService struct {
+	accountRepo   repository.IAccountRepository
+	customerRepo  repository.ICustomerRepository
+	statementRepo repository.IStatementRepository
+	pageTokens    *pagetoken.Signer
+}
+
+// NewStatementService creates a new instance of StatementService
+func NewStatementService(accountRepo repository.IAccountRepository, customerRepo repository.ICustomerRepository, statementRepo repository.IStatementRepository, pageTokens *pagetoken.Signer) IStatementService {
+	return &StatementService{
+		accountRepo:   accountRepo,
+		customerRepo:  customerRepo,
+		statementRepo: statementRepo,
+		pageTokens:    pageTokens,
+	}
+}
+
+// statementPeriod is the validated account and date range of a statement
+type statementPeriod struct {
+	account  *models.Account
+	from, to time.Time // to is the start of the day after the last day
+}
+
+// GetStatement returns the summary of a statement period and one page of
+// its lines with running balances
+func (s *StatementService) GetStatement(ctx context.Context, customerID string, req *pb.GetStatementRequest) (*pb.GetStatementResponse, error) {
+	ctx, span := tracer.Start(ctx, "StatementService.GetStatement")
+	defer span.End()
+
+	period, err := s.period(ctx, customerID, req.AccountId, req.FromDate, req.ToDate)
+	if err != nil {
+		return nil, err
+	}
+
+	pageSize := int(req.PageSize)
+	if pageSize <= 0 {
+		pageSize = defaultStatementPageSize
+	}
+
+	// Tokens of one account's statement are no good for another's
+	scope := "statement:" + period.account.ID
+	var cursor *repository.StatementCursor
+	if req.PageToken != "" {
+		position, err := s.pageTokens.Decode(scope, req.PageToken)
+		if err != nil {
+			return nil, invalidField("page_token", "page token is not valid")
+		}
+		cursor = &repository.StatementCursor{PostedAt: position.Time, PostingID: position.ID}
+	}
+
+	summary, err := s.summary(ctx, period)
+	if err != nil {
+		return nil, err
+	}
+
+	// Fetch one extra line to learn whether another page follows
+	lines, err := s.statementRepo.FindLines(ctx, period.account.ID, period.from, period.to, cursor, pageSize+1)
+	if err != nil {
+		return nil, fmt.Errorf("failed to retrieve statement lines: %w", err)
+	}
+
+	response := &pb.GetStatementResponse{Summary: summary}
+	if len(lines) > pageSize {
+		lines = lines[:pageSize]
+		last := lines[len(lines)-1]
+		response.NextPageToken = s.pageTokens.Encode(scope, pagetoken.Cursor{Time: last.PostedAt, ID: last.PostingID})
+	}
+
+	balance := summary.OpeningBalance.MinorUnits
+	if cursor != nil {
+		balance, err = s.statementRepo.BalanceBefore(ctx, period.account.ID, period.from, cursor)
+		if err != nil {
+			return nil, fmt.Errorf("failed to compute statement balance: %w", err)
+		}
+	}
+	response.Lines, _ = toStatementLines(period.account.Currency, lines, balance)
+
+	return response, nil
+}
+
+// StreamStatement sends the summary of a statement period followed by all
+// of its lines in chunks
+func (s *StatementService) StreamStatement(ctx context.Context, customerID string, req *pb.StreamStatementRequest, send func(*pb.StatementChunk) error) error {
+	ctx, span := tracer.Start(ctx, "StatementService.StreamStatement")
+	defer span.End()
+
+	period, err := s.period(ctx, customerID, req.AccountId, req.FromDate, req.ToDate)
+	if err != nil {
+		return err
+	}
+
+	summary, err := s.summary(ctx, period)
+	if err != nil {
+		return err
+	}
+	if err := send(&pb.StatementChunk{Summary: summary}); err != nil {
+		return err
+	}
+
+	balance := summary.OpeningBalance.MinorUnits
+	return s.forEachChunk(ctx, period, func(lines []*repository.StatementLine) error {
+		chunk := &pb.StatementChunk{}
+		chunk.Lines, balance = toStatementLines(period.account.Currency, lines, balance)
+		return send(chunk)
+	})
+}
+
+// ExportStatement renders a statement period as a CSV or PDF file
+func (s *StatementService) ExportStatement(ctx context.Context, customerID string, req *pb.ExportStatementRequest) (*StatementFile, error) {
+	ctx, span := tracer.Start(ctx, "StatementService.ExportStatement")
+	defer span.End()
+
+	renderer, err := statementexport.ForFormat(req.Format)
+	if err != nil {
+		return nil, invalidField("format", "format must be csv or pdf")
+	}
+
+	period, err := s.period(ctx, customerID, req.AccountId, req.FromDate, req.ToDate)
+	if err != nil {
+		return nil, err
+	}
+	if period.to.Sub(period.from) > maxExportDays*24*time.Hour {
+		return nil, invalidField("to_date", fmt.Sprintf("an export covers at most %d days", maxExportDays))
+	}
+	account := period.account
+
+	customer, err := s.customerRepo.FindByID(ctx, account.CustomerID)
+	if err != nil {
+		return nil, err
+	}
+
+	summary, err := s.summary(ctx, period)
+	if err != nil {
+		return nil, err
+	}
+	if summary.LineCount > maxExportLines {
+		return nil, ErrStatementTooLarge
+	}
+
+	statement := &statementexport.Statement{
+		CustomerName:    strings.TrimSpace(customer.FirstName + " " + customer.LastName),
+		CustomerAddress: customer.Address,
+		AccountNumber:   account.AccountNumber,
+		IBAN:            account.IBAN,
+		AccountType:     account.AccountType,
+		Currency:        account.Currency,
+		From:            period.from,
+		To:              period.to.AddDate(0, 0, -1),
+		OpeningBalance:  summary.OpeningBalance.MinorUnits,
+		ClosingBalance:  summary.ClosingBalance.MinorUnits,
+		TotalCredits:    summary.TotalCredits.MinorUnits,
+		TotalDebits:     summary.TotalDebits.MinorUnits,
+		GeneratedAt:     time.Now(),
+	}
+
+	balance := statement.OpeningBalance
+	err = s.forEachChunk(ctx, period, func(lines []*repository.StatementLine) error {
+		// Lines posted after the summary was read still count
+		if len(statement.Lines)+len(lines) > maxExportLines {
+			return ErrStatementTooLarge
+		}
+		for _, line := range lines {
+			balance += line.Amount
+			statement.Lines = append(statement.Lines, statementexport.Line{
+				PostedAt:      line.PostedAt,
+				TransactionID: line.EntryID,
+				EntryType:     line.EntryType,
+				Reference:     line.Reference,
+				Description:   line.Description,
+				Amount:        line.Amount,
+				Balance:       balance,
+			})
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	var buf bytes.Buffer
+	if err := renderer.Render(&buf, statement); err != nil {
+		return nil, fmt.Errorf("failed to render statement: %w", err)
+	}
+
+	return &StatementFile{
+		ContentType: renderer.ContentType(),
+		Filename:    statementexport.Filename(statement, renderer),
+		Data:        buf.Bytes(),
+	}, nil
+}
+
+// forEachChunk reads every line of a statement period in posting order,
+// handing them to fn in chunks of statementChunkSize
+func (s *StatementService) forEachChunk(ctx context.Context, period *statementPeriod, fn func(lines []*repository.StatementLine) error) error {
+	var cursor *repository.StatementCursor
+	for {
+		lines, err := s.statementRepo.FindLines(ctx, period.account.ID, period.from, period.to, cursor, statementChunkSize)
+		if err != nil {
+			return fmt.Errorf("failed to retrieve statement lines: %w", err)
+		}
+		if len(lines) == 0 {
+			return nil
+		}
+		if err := fn(lines); err != nil {
+			return err
+		}
+		if len(lines) < statementChunkSize {
+			return nil
+		}
+
+		last := lines[len(lines)-1]
+		cursor = &repository.StatementCursor{PostedAt: last.PostedAt, PostingID: last.PostingID}
+	}
+}
+
+// period checks the account and date range of a statement request
+func (s *StatementService) period(ctx context.Context, customerID, accountID, fromDate, toDate string) (*statementPeriod, error) {
+	if accountID == "" {
+		return nil, invalidField("account_id", "account ID is required")
+	}
+
+	from, err := time.Parse(statementDateLayout, fromDate)
+	if err != nil {
+		return nil, invalidField("from_date", "from date must be a date such as 2024-01-31")
+	}
+	to, err := time.Parse(statementDateLayout, toDate)
+	if err != nil {
+		return nil, invalidField("to_date", "to date must be a date such as 2024-01-31")
+	}
+	if to.Before(from) {
+		return nil, invalidField("to_date", "to date must not be before from date")
+	}
+
+	account, err := s.accountRepo.FindByID(ctx, accountID)
+	if err != nil {
+		return nil, err
+	}
+	// Other customers' accounts look the same as missing ones
+	if customerID != "" && account.CustomerID != customerID {
+		return nil, &repository.NotFoundError{Resource: "account"}
+	}
+
+	return &statementPeriod{account: account, from: from, to: to.AddDate(0, 0, 1)}, nil
+}
+
+// summary computes the balances and totals of a statement period
+func (s *StatementService) summary(ctx context.Context, period *statementPeriod) (*pb.StatementSummary, error) {
+	account := period.account
+
+	opening, err := s.statementRepo.BalanceBefore(ctx, account.ID, period.from, nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to compute opening balance: %w", err)
+	}
+	totals, err := s.statementRepo.Totals(ctx, account.ID, period.from, period.to)
+	if err != nil {
+		return nil, fmt.Errorf("failed to compute statement totals: %w", err)
+	}
+
+	return &pb.StatementSummary{
+		AccountId:      account.ID,
+		AccountNumber:  account.AccountNumber,
+		Currency:       account.Currency,
+		FromDate:       period.from.Format(statementDateLayout),
+		ToDate:         period.to.AddDate(0, 0, -1).Format(statementDateLayout),
+		OpeningBalance: toMoney(account.Currency, opening),
+		ClosingBalance: toMoney(account.Currency, opening+totals.Credits-totals.Debits),
+		TotalCredits:   toMoney(account.Currency, totals.Credits),
+		TotalDebits:    toMoney(account.Currency, totals.Debits),
+		LineCount:      int32(totals.Count),
+	}, nil
+}
+
+// toStatementLines converts lines to messages, carrying the running balance
+// forward from balance. It returns the balance after the last line.
+func toStatementLines(currency string, lines []*repository.StatementLine, balance int64) ([]*pb.StatementLine, int64) {
+	messages := make([]*pb.StatementLine, len(lines))
+	for i, line := range lines {
+		balance += line.Amount
+		messages[i] = &pb.StatementLine{
+			TransactionId: line.EntryID,
+			PostedAt:      line.PostedAt.Format(time.RFC3339),
+			EntryType:     line.EntryType,
+			Reference:     line.Reference,
+			Description:   line.Description,
+			Amount:        toMoney(currency, line.Amount),
+			Balance:       toMoney(currency, balance),
+		}
+	}
+	return messages, balance
+}
@@ -0,0 +1,7 @@
+package service
+
+import "go.opentelemetry.io/otel"
+
+// tracer creates the service layer spans, children of the RPC span started
+// by the gRPC stats handler
+var tracer = otel.Tracer("github.com/paudelanil/grpc-crud/internal/service")
@@ -0,0 +1,71 @@
+package statementexport
+
+import (
+	"encoding/csv"
+	"io"
+	"strings"
+	"time"
+
+	"github.com/paudelanil/grpc-crud/internal/money"
+)
+
+// CSVRenderer writes a statement as a CSV table with one row per
+// transaction, between opening and closing balance rows
+type CSVRenderer struct{}
+
+func (CSVRenderer) ContentType() string { return "text/csv; charset=utf-8" }
+
+func (CSVRenderer) Extension() string { return "csv" }
+
+// Render writes the statement
+func (CSVRenderer) Render(w io.Writer, statement *Statement) error {
+	out := csv.NewWriter(w)
+	format := func(units int64) string {
+		if units == 0 {
+			return ""
+		}
+		return money.Format(statement.Currency, units)
+	}
+
+	rows := [][]string{
+		{"Date", "Transaction ID", "Type", "Reference", "Description", "Debit", "Credit", "Balance", "Currency"},
+		{statement.From.Format(dateLayout), "", "", "", "Opening balance", "", "", money.Format(statement.Currency, statement.OpeningBalance), statement.Currency},
+	}
+	if err := out.WriteAll(rows); err != nil {
+		return err
+	}
+
+	for _, line := range statement.Lines {
+		debit, credit := splitAmount(line.Amount)
+		row := []string{
+			line.PostedAt.UTC().Format(time.RFC3339),
+			line.TransactionID,
+			line.EntryType,
+			csvSafe(line.Reference),
+			csvSafe(line.Description),
+			format(debit),
+			format(credit),
+			money.Format(statement.Currency, line.Balance),
+			statement.Currency,
+		}
+		if err := out.Write(row); err != nil {
+			return err
+		}
+	}
+
+	closing := []string{statement.To.Format(dateLayout), "", "", "", "Closing balance", format(statement.TotalDebits), format(statement.TotalCredits), money.Format(statement.Currency, statement.ClosingBalance), statement.Currency}
+	if err := out.Write(closing); err != nil {
+		return err
+	}
+
+	out.Flush()
+	return out.Error()
+}
+
+// csvSafe stops spreadsheets from running free text as a formula
+func csvSafe(s string) string {
+	if s != "" && strings.ContainsRune("=+-@\t\r", rune(s[0])) {
+		return "'" + s
+	}
+	return s
+}
@@ -0,0 +1,80 @@
+// Package statementexport renders account statements as files for
+// printing and spreadsheets.
+package statementexport
+
+import (
+	"fmt"
+	"io"
+	"strings"
+	"time"
+)
+
+// Export formats
+const (
+	FormatCSV = "csv"
+	FormatPDF = "pdf"
+)
+
+// Statement holds everything printed on a statement. Amounts are minor
+// units of Currency.
+type Statement struct {
+	CustomerName    string
+	CustomerAddress string
+	AccountNumber   string
+	IBAN            string // empty when the bank issues no IBANs
+	AccountType     string
+	Currency        string
+	From, To        time.Time // first and last day, inclusive
+	OpeningBalance  int64
+	ClosingBalance  int64
+	TotalCredits    int64
+	TotalDebits     int64 // a positive amount
+	Lines           []Line
+	GeneratedAt     time.Time
+}
+
+// Line is one transaction on a statement
+type Line struct {
+	PostedAt      time.Time
+	TransactionID string
+	EntryType     string
+	Reference     string
+	Description   string
+	Amount        int64 // positive for credits, negative for debits
+	Balance       int64 // running balance after the line
+}
+
+// Renderer writes a statement in one file format
+type Renderer interface {
+	ContentType() string
+	Extension() string
+	Render(w io.Writer, statement *Statement) error
+}
+
+// ForFormat returns the renderer of a format
+func ForFormat(format string) (Renderer, error) {
+	switch strings.ToLower(format) {
+	case FormatCSV:
+		return CSVRenderer{}, nil
+	case FormatPDF:
+		return PDFRenderer{}, nil
+	}
+	return nil, fmt.Errorf("unknown statement format %q", format)
+}
+
+// Filename returns the download name of a statement file, e.g.
+// statement-0011000000004204-2024-01-01-2024-01-31.pdf
+func Filename(statement *Statement, renderer Renderer) string {
+	return fmt.Sprintf("statement-%s-%s-%s.%s", statement.AccountNumber,
+		statement.From.Format(dateLayout), statement.To.Format(dateLayout), renderer.Extension())
+}
+
+const dateLayout = "2006-01-02"
+
+// splitAmount returns the debit and credit columns of a line amount
+func splitAmount(amount int64) (debit, credit int64) {
+	if amount < 0 {
+		return -amount, 0
+	}
+	return 0, amount
+}
@@ -0,0 +1,166 @@
+package statementexport
+
+import (
+	"fmt"
+	"io"
+
+	"github.com/go-pdf/fpdf"
+	"github.com/paudelanil/grpc-crud/internal/money"
+)
+
+// PDFRenderer writes a printable A4 statement
+type PDFRenderer struct{}
+
+func (PDFRenderer) ContentType() string { return "application/pdf" }
+
+func (PDFRenderer) Extension() string { return "pdf" }
+
+// Table columns: title, width in mm and alignment
+var pdfColumns = []struct {
+	title string
+	width float64
+	align string
+}{
+	{"Date", 22, "L"},
+	{"Description", 58, "L"},
+	{"Reference", 30, "L"},
+	{"Debit", 24, "R"},
+	{"Credit", 24, "R"},
+	{"Balance", 28, "R"},
+}
+
+const (
+	pdfLineHeight = 6
+	pdfMargin     = 15
+)
+
+// Render writes the statement
+func (PDFRenderer) Render(w io.Writer, statement *Statement) error {
+	pdf := fpdf.New("P", "mm", "A4", "")
+	pdf.SetMargins(pdfMargin, pdfMargin, pdfMargin)
+	pdf.SetAutoPageBreak(true, pdfMargin+5)
+	pdf.SetCreationDate(statement.GeneratedAt)
+	pdf.SetTitle("Account statement "+statement.AccountNumber, true)
+	pdf.AliasNbPages("")
+
+	// The core fonts only cover Windows-1252; other characters print as "?"
+	text := pdf.UnicodeTranslatorFromDescriptor("")
+	amount := func(units int64) string {
+		if units == 0 {
+			return ""
+		}
+		return money.Format(statement.Currency, units)
+	}
+
+	pdf.SetFooterFunc(func() {
+		pdf.SetY(-pdfMargin)
+		pdf.SetFont("Helvetica", "I", 8)
+		pdf.CellFormat(0, 5, fmt.Sprintf("Generated %s - page %d of {nb}",
+			statement.GeneratedAt.UTC().Format("2006-01-02 15:04 MST"), pdf.PageNo()), "", 0, "C", false, 0, "")
+	})
+
+	tableHeader := func() {
+		pdf.SetFont("Helvetica", "B", 9)
+		pdf.SetFillColor(230, 230, 230)
+		for _, col := range pdfColumns {
+			pdf.CellFormat(col.width, pdfLineHeight, col.title, "1", 0, col.align, true, 0, "")
+		}
+		pdf.Ln(-1)
+	}
+	// row draws a table row in a font style, "" or "B". The style is set
+	// after any page break, which changes the font for the header.
+	row := func(style string, cells ...string) {
+		// Start a new page, with the table header, before a row that does not fit
+		_, pageHeight := pdf.GetPageSize()
+		if pdf.GetY()+pdfLineHeight > pageHeight-pdfMargin-5 {
+			pdf.AddPage()
+			tableHeader()
+		}
+		pdf.SetFont("Helvetica", style, 9)
+		for i, col := range pdfColumns {
+			pdf.CellFormat(col.width, pdfLineHeight, fit(pdf, text(cells[i]), col.width-2), "LR", 0, col.align, false, 0, "")
+		}
+		pdf.Ln(-1)
+	}
+
+	pdf.AddPage()
+
+	pdf.SetFont("Helvetica", "B", 16)
+	pdf.CellFormat(0, 10, "Account statement", "", 1, "L", false, 0, "")
+
+	pdf.SetFont("Helvetica", "", 10)
+	pdf.CellFormat(0, 5, text(statement.CustomerName), "", 1, "L", false, 0, "")
+	if statement.CustomerAddress != "" {
+		pdf.MultiCell(90, 5, text(statement.CustomerAddress), "", "L", false)
+	}
+	pdf.Ln(4)
+
+	details := [][2]string{
+		{"Account number", statement.AccountNumber},
+		{"Account type", statement.AccountType},
+		{"Currency", statement.Currency},
+		{"Period", fmt.Sprintf("%s to %s", statement.From.Format(dateLayout), statement.To.Format(dateLayout))},
+	}
+	if statement.IBAN != "" {
+		details = append(details[:1], append([][2]string{{"IBAN", statement.IBAN}}, details[1:]...)...)
+	}
+	for _, d := range details {
+		pdf.SetFont("Helvetica", "B", 10)
+		pdf.CellFormat(40, 5, d[0], "", 0, "L", false, 0, "")
+		pdf.SetFont("Helvetica", "", 10)
+		pdf.CellFormat(0, 5, text(d[1]), "", 1, "L", false, 0, "")
+	}
+	pdf.Ln(4)
+
+	summary := [][2]string{
+		{"Opening balance", money.Format(statement.Currency, statement.OpeningBalance)},
+		{"Total credits", money.Format(statement.Currency, statement.TotalCredits)},
+		{"Total debits", money.Format(statement.Currency, statement.TotalDebits)},
+		{"Closing balance", money.Format(statement.Currency, statement.ClosingBalance)},
+	}
+	for _, s := range summary {
+		pdf.SetFont("Helvetica", "B", 10)
+		pdf.CellFormat(40, 5, s[0], "", 0, "L", false, 0, "")
+		pdf.SetFont("Helvetica", "", 10)
+		pdf.CellFormat(35, 5, s[1], "", 1, "R", false, 0, "")
+	}
+	pdf.Ln(6)
+
+	tableHeader()
+	row("", statement.From.Format(dateLayout), "Opening balance", "", "", "", money.Format(statement.Currency, statement.OpeningBalance))
+	for _, line := range statement.Lines {
+		debit, credit := splitAmount(line.Amount)
+		description := line.Description
+		if description == "" {
+			description = line.EntryType
+		}
+		row("", line.PostedAt.UTC().Format(dateLayout), description, line.Reference,
+			amount(debit), amount(credit), money.Format(statement.Currency, line.Balance))
+	}
+	row("B", statement.To.Format(dateLayout), "Closing balance", "", amount(statement.TotalDebits), amount(statement.TotalCredits),
+		money.Format(statement.Currency, statement.ClosingBalance))
+
+	// Close the table
+	width := 0.0
+	for _, col := range pdfColumns {
+		width += col.width
+	}
+	pdf.CellFormat(width, 0, "", "T", 1, "L", false, 0, "")
+
+	if err := pdf.Error(); err != nil {
+		return err
+	}
+	return pdf.Output(w)
+}
+
+// fit shortens s with an ellipsis until it fits in width millimetres. s is
+// already translated to the single-byte font encoding.
+func fit(pdf *fpdf.Fpdf, s string, width float64) string {
+	if pdf.GetStringWidth(s) <= width {
+		return s
+	}
+	for len(s) > 0 && pdf.GetStringWidth(s+"...") > width {
+		s = s[:len(s)-1]
+	}
+	return s + "..."
+}
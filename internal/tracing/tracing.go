@@ -0,0 +1,125 @@
+package tracing
+
+import (
+	"context"
+	"fmt"
+	"os"
+
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/exporters/otlp/otlptrace/otlptracegrpc"
+	"go.opentelemetry.io/otel/exporters/stdout/stdouttrace"
+	"go.opentelemetry.io/otel/propagation"
+	"go.opentelemetry.io/otel/sdk/resource"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+	semconv "go.opentelemetry.io/otel/semconv/v1.37.0"
+)
+
+// Span exporters
+const (
+	ExporterNone   = "none"
+	ExporterOTLP   = "otlp"
+	ExporterStdout = "stdout"
+	ExporterMemory = "memory" // keeps spans until shutdown, for local testing
+)
+
+// Options selects where spans are sent and how many are kept
+type Options struct {
+	ServiceName string
+	Exporter    string
+	Endpoint    string // OTLP collector host:port; empty uses OTEL_EXPORTER_OTLP_ENDPOINT
+	Insecure    bool   // send OTLP without TLS
+	SampleRatio float64
+
+	// SpanExporter, when set, also receives every span as soon as it ends,
+	// alongside Exporter. It cannot be chosen from the configuration.
+	SpanExporter sdktrace.SpanExporter
+}
+
+// Provider owns the tracer provider installed as the global one
+type Provider struct {
+	tp     *sdktrace.TracerProvider
+	memory *tracetest.InMemoryExporter
+}
+
+// Setup installs a global tracer provider and the W3C trace context and
+// baggage propagators, so incoming trace headers are continued. With the
+// none exporter spans are still created for propagation but never exported.
+func Setup(ctx context.Context, opts Options) (*Provider, error) {
+	p := &Provider{}
+
+	var exporter sdktrace.SpanExporter
+	switch opts.Exporter {
+	case ExporterNone, "":
+	case ExporterOTLP:
+		clientOpts := []otlptracegrpc.Option{}
+		if opts.Endpoint != "" {
+			clientOpts = append(clientOpts, otlptracegrpc.WithEndpoint(opts.Endpoint))
+		}
+		if opts.Insecure {
+			clientOpts = append(clientOpts, otlptracegrpc.WithInsecure())
+		}
+		otlp, err := otlptracegrpc.New(ctx, clientOpts...)
+		if err != nil {
+			return nil, fmt.Errorf("failed to create OTLP exporter: %w", err)
+		}
+		exporter = otlp
+	case ExporterStdout:
+		stdout, err := stdouttrace.New(stdouttrace.WithWriter(os.Stdout), stdouttrace.WithPrettyPrint())
+		if err != nil {
+			return nil, fmt.Errorf("failed to create stdout exporter: %w", err)
+		}
+		exporter = stdout
+	case ExporterMemory:
+		p.memory = tracetest.NewInMemoryExporter()
+		exporter = p.memory
+	default:
+		return nil, fmt.Errorf("unknown span exporter %q", opts.Exporter)
+	}
+
+	res, err := resource.Merge(resource.Default(), resource.NewWithAttributes(
+		semconv.SchemaURL,
+		semconv.ServiceName(opts.ServiceName),
+	))
+	if err != nil {
+		return nil, fmt.Errorf("failed to build trace resource: %w", err)
+	}
+
+	providerOpts := []sdktrace.TracerProviderOption{
+		sdktrace.WithResource(res),
+		sdktrace.WithSampler(sdktrace.ParentBased(sdktrace.TraceIDRatioBased(opts.SampleRatio))),
+	}
+	switch {
+	case p.memory != nil:
+		// Export synchronously so spans are visible as soon as they end
+		providerOpts = append(providerOpts, sdktrace.WithSyncer(exporter))
+	case exporter != nil:
+		providerOpts = append(providerOpts, sdktrace.WithBatcher(exporter))
+	}
+	if opts.SpanExporter != nil {
+		providerOpts = append(providerOpts, sdktrace.WithSyncer(opts.SpanExporter))
+	}
+
+	p.tp = sdktrace.NewTracerProvider(providerOpts...)
+	otel.SetTracerProvider(p.tp)
+	otel.SetTextMapPropagator(propagation.NewCompositeTextMapPropagator(
+		propagation.TraceContext{},
+		propagation.Baggage{},
+	))
+
+	return p, nil
+}
+
+// Spans returns the spans recorded by the memory exporter, or nil for any
+// other exporter
+func (p *Provider) Spans() tracetest.SpanStubs {
+	if p.memory == nil {
+		return nil
+	}
+	return p.memory.GetSpans()
+}
+
+// Shutdown flushes buffered spans and stops the exporter
+func (p *Provider) Shutdown(ctx context.Context) error {
+	return p.tp.Shutdown(ctx)
+}
@@ -0,0 +1,56 @@
+package tracing
+
+import (
+	"context"
+	"testing"
+
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+)
+
+func TestSetupExportsToSpanExporter(t *testing.T) {
+	exporter := tracetest.NewInMemoryExporter()
+	provider, err := Setup(context.Background(), Options{
+		ServiceName:  "grpc-crud-test",
+		Exporter:     ExporterNone,
+		SampleRatio:  1,
+		SpanExporter: exporter,
+	})
+	if err != nil {
+		t.Fatalf("Setup: %v", err)
+	}
+	t.Cleanup(func() { provider.Shutdown(context.Background()) })
+
+	_, span := otel.Tracer("test").Start(context.Background(), "operation")
+	span.End()
+
+	spans := exporter.GetSpans()
+	if len(spans) != 1 || spans[0].Name != "operation" {
+		t.Fatalf("exported spans = %v, want one span named operation", spans.Snapshots())
+	}
+}
+
+func TestSetupMemoryExporterWithSpanExporter(t *testing.T) {
+	extra := tracetest.NewInMemoryExporter()
+	provider, err := Setup(context.Background(), Options{
+		ServiceName:  "grpc-crud-test",
+		Exporter:     ExporterMemory,
+		SampleRatio:  1,
+		SpanExporter: extra,
+	})
+	if err != nil {
+		t.Fatalf("Setup: %v", err)
+	}
+	t.Cleanup(func() { provider.Shutdown(context.Background()) })
+
+	_, span := otel.Tracer("test").Start(context.Background(), "operation")
+	span.End()
+
+	// Both exporters see the span
+	if spans := provider.Spans(); len(spans) != 1 || spans[0].Name != "operation" {
+		t.Fatalf("memory exporter spans = %v, want one span named operation", spans)
+	}
+	if spans := extra.GetSpans(); len(spans) != 1 || spans[0].Name != "operation" {
+		t.Fatalf("span exporter spans = %v, want one span named operation", spans.Snapshots())
+	}
+}
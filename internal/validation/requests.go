@@ -0,0 +1,206 @@
+package validation
+
+import (
+	"github.com/paudelanil/grpc-crud/internal/statementexport"
+	"github.com/paudelanil/grpc-crud/models"
+	"github.com/paudelanil/grpc-crud/pb"
+)
+
+// Limits shared by the request rules
+const (
+	maxNameLength    = 100
+	maxAddressLength = 255
+	maxEmailLength   = 254
+	maxPageSize      = 100
+	maxReference     = 64
+	maxDescription   = 255
+	maxProductCode   = 20
+	maxPageToken     = 512
+)
+
+// accountStatuses are the values accepted for account status. Account
+// types are product codes, checked against the catalog by the service.
+var accountStatuses = []string{
+	models.AccountStatusPending,
+	models.AccountStatusActive,
+	models.AccountStatusDormant,
+	models.AccountStatusFrozen,
+	models.AccountStatusClosed,
+}
+
+// page rules apply to every paginated list request
+var page = Fields{
+	"page_number":      {Range(0, 1_000_000)},
+	"page_size":        {Range(0, maxPageSize)},
+	"page_token":       {MaxLength(maxPageToken)},
+	"total_count_mode": {OneOf("exact", "estimated", "none")},
+}
+
+// amount rules apply to every Money amount in a request
+var amount = Fields{
+	"amount":             {Required()},
+	"amount.currency":    {Required(), Currency()},
+	"amount.minor_units": {Required(), Positive()},
+	"reference":          {MaxLength(maxReference)},
+	"description":        {MaxLength(maxDescription)},
+}
+
+// product rules apply to the product of create and update requests
+var product = Fields{
+	"product":                           {Required()},
+	"product.code":                      {Required(), MaxLength(maxProductCode)},
+	"product.name":                      {Required(), MaxLength(maxNameLength)},
+	"product.description":               {MaxLength(maxDescription)},
+	"product.number_prefix":             {Required()},
+	"product.max_accounts_per_customer": {Range(0, 1000)},
+}
+
+// Requests returns a Validator with the rules of every request message
+func Requests() *Validator {
+	v := New()
+
+	// LoginService
+	v.Register(&pb.UserRegisterRequest{}, Fields{
+		"username": {Required(), Length(3, 50)},
+		"email":    {Required(), MaxLength(maxEmailLength), Email()},
+		"password": {Required(), Length(8, 72)}, // bcrypt ignores bytes past 72
+	})
+	v.Register(&pb.UserLoginRequest{}, Fields{
+		"username": {Required()},
+		"password": {Required()},
+	})
+	v.Register(&pb.UserLogoutRequest{}, Fields{
+		"access_token": {Required()},
+	})
+	v.Register(&pb.TokenRequest{}, Fields{
+		"refresh_token": {Required()},
+	})
+	v.Register(&pb.UpdateUserRoleRequest{}, Fields{
+		"user_id": {Required(), UUID()},
+		"role":    {Required(), OneOf(models.RoleAdmin, models.RoleTeller, models.RoleAuditor, models.RoleCustomer)},
+	})
+	v.Register(&pb.LinkCustomerRequest{}, Fields{
+		"user_id":     {Required(), UUID()},
+		"customer_id": {Required(), UUID()},
+	})
+
+	// AccountService customers
+	v.Register(&pb.CreateCustomerRequest{}, Fields{
+		"first_name":   {Required(), MaxLength(maxNameLength)},
+		"last_name":    {Required(), MaxLength(maxNameLength)},
+		"email":        {Required(), MaxLength(maxEmailLength), Email()},
+		"phone_number": {Required(), Phone()},
+		"address":      {MaxLength(maxAddressLength)},
+	})
+	v.Register(&pb.GetCustomerRequest{}, Fields{
+		"customer_id": {UUID()}, // customers may leave it out to read their own profile
+	})
+	v.Register(&pb.UpdateCustomerRequest{}, Fields{
+		"customer_id":  {Required(), UUID()},
+		"first_name":   {MaxLength(maxNameLength)},
+		"last_name":    {MaxLength(maxNameLength)},
+		"email":        {MaxLength(maxEmailLength), Email()},
+		"phone_number": {Phone()},
+		"address":      {MaxLength(maxAddressLength)},
+	})
+	v.Register(&pb.DeleteCustomerRequest{}, Fields{
+		"customer_id": {Required(), UUID()},
+	})
+	v.Register(&pb.ListCustomerRequest{}, page)
+
+	// AccountService accounts
+	v.Register(&pb.CreateAccountRequest{}, Fields{
+		"customer_id":                 {Required(), UUID()},
+		"account_type":                {MaxLength(maxProductCode)},
+		"currency":                    {Currency()},
+		"opening_deposit.currency":    {Currency()}, // the deposit is optional
+		"opening_deposit.minor_units": {Positive()},
+	})
+	v.Register(&pb.GetAccountRequest{}, Fields{
+		"account_id":     {UUID()}, // either the ID or the number identifies the account
+		"account_number": {AccountNumber()},
+	})
+	v.Register(&pb.UpdateAccountRequest{}, Fields{
+		"account_id":   {Required(), UUID()},
+		"account_type": {MaxLength(maxProductCode)},
+		"status":       {OneOf(accountStatuses...)},
+		"status_note":  {MaxLength(maxDescription)},
+	})
+	v.Register(&pb.DeleteAccountRequest{}, Fields{
+		"account_id": {Required(), UUID()},
+	})
+	v.Register(&pb.ListAccountRequest{}, page)
+
+	// AccountService money movement
+	v.Register(&pb.DepositRequest{}, with(amount, Fields{
+		"account_id": {Required(), UUID()},
+	}))
+	v.Register(&pb.WithdrawRequest{}, with(amount, Fields{
+		"account_id": {Required(), UUID()},
+	}))
+	v.Register(&pb.TransferRequest{}, with(amount, Fields{
+		"from_account_id": {Required(), UUID()},
+		"to_account_id":   {Required(), UUID()},
+	}))
+
+	// AccountService lifecycle
+	v.Register(&pb.FreezeAccountRequest{}, Fields{
+		"account_id": {Required(), UUID()},
+		"reason":     {Required(), OneOf(models.StatusReasons[models.AccountStatusFrozen]...)},
+		"note":       {MaxLength(maxDescription)},
+	})
+	v.Register(&pb.UnfreezeAccountRequest{}, Fields{
+		"account_id": {Required(), UUID()},
+		"reason":     {Required(), OneOf(models.StatusReasons[models.AccountStatusActive]...)},
+		"note":       {MaxLength(maxDescription)},
+	})
+	v.Register(&pb.CloseAccountRequest{}, Fields{
+		"account_id": {Required(), UUID()},
+		"reason":     {Required(), OneOf(models.StatusReasons[models.AccountStatusClosed]...)},
+		"note":       {MaxLength(maxDescription)},
+	})
+	v.Register(&pb.GetAccountStatusHistoryRequest{}, Fields{
+		"account_id": {Required(), UUID()},
+	})
+
+	// AccountStatementService
+	v.Register(&pb.GetStatementRequest{}, Fields{
+		"account_id": {Required(), UUID()},
+		"from_date":  {Required(), Date()},
+		"to_date":    {Required(), Date()},
+		"page_size":  {Range(0, maxPageSize)},
+		"page_token": {MaxLength(maxPageToken)},
+	})
+	v.Register(&pb.StreamStatementRequest{}, Fields{
+		"account_id": {Required(), UUID()},
+		"from_date":  {Required(), Date()},
+		"to_date":    {Required(), Date()},
+	})
+
+	v.Register(&pb.ExportStatementRequest{}, Fields{
+		"account_id": {Required(), UUID()},
+		"from_date":  {Required(), Date()},
+		"to_date":    {Required(), Date()},
+		"format":     {Required(), OneOf(statementexport.FormatCSV, statementexport.FormatPDF)},
+	})
+
+	// ProductService
+	v.Register(&pb.CreateProductRequest{}, product)
+	v.Register(&pb.GetProductRequest{}, Fields{
+		"code": {Required(), MaxLength(maxProductCode)},
+	})
+	v.Register(&pb.UpdateProductRequest{}, product)
+
+	return v
+}
+
+// with merges rule sets; later sets win for the same field
+func with(sets ...Fields) Fields {
+	merged := make(Fields)
+	for _, set := range sets {
+		for path, rules := range set {
+			merged[path] = rules
+		}
+	}
+	return merged
+}
@@ -0,0 +1,286 @@
+package validation
+
+import (
+	"fmt"
+	"net/mail"
+	"regexp"
+	"slices"
+	"sort"
+	"strings"
+	"time"
+	"unicode/utf8"
+
+	"github.com/google/uuid"
+	"github.com/paudelanil/grpc-crud/internal/accountnumber"
+	"github.com/paudelanil/grpc-crud/internal/money"
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/reflect/protoreflect"
+)
+
+// Violation describes one invalid request field
+type Violation struct {
+	Field       string // field path, e.g. amount.currency
+	Description string
+}
+
+// Rule checks the value of one field. It returns a description of the
+// problem, or "" when the value is valid. Rules other than Required accept
+// unset fields, so optional fields are only checked when they are given.
+type Rule func(field string, value protoreflect.Value, set bool) string
+
+// Fields maps field paths, such as email or amount.currency, to their rules
+type Fields map[string][]Rule
+
+// Validator checks request messages against the rules registered for
+// their type. Messages without rules are always valid.
+type Validator struct {
+	rules map[protoreflect.FullName]Fields
+}
+
+// New creates an empty Validator
+func New() *Validator {
+	return &Validator{rules: make(map[protoreflect.FullName]Fields)}
+}
+
+// Register sets the rules of a message type. It panics when a field path
+// does not exist, so a typo in the rules fails at startup.
+func (v *Validator) Register(msg proto.Message, fields Fields) {
+	desc := msg.ProtoReflect().Descriptor()
+	for path := range fields {
+		if _, err := resolveField(desc, path); err != nil {
+			panic(fmt.Sprintf("validation: %s: %v", desc.FullName(), err))
+		}
+	}
+	v.rules[desc.FullName()] = fields
+}
+
+// Validate returns every violation of the message, ordered by field
+// number so the result is stable
+func (v *Validator) Validate(msg proto.Message) []Violation {
+	m := msg.ProtoReflect()
+	fields, ok := v.rules[m.Descriptor().FullName()]
+	if !ok {
+		return nil
+	}
+
+	var violations []Violation
+	for _, path := range sortedPaths(m.Descriptor(), fields) {
+		value, set := lookup(m, path)
+		for _, rule := range fields[path] {
+			if problem := rule(path, value, set); problem != "" {
+				violations = append(violations, Violation{Field: path, Description: problem})
+				break // report one problem per field
+			}
+		}
+	}
+	return violations
+}
+
+// resolveField checks that a dotted field path exists in the message
+func resolveField(desc protoreflect.MessageDescriptor, path string) (protoreflect.FieldDescriptor, error) {
+	parts := strings.Split(path, ".")
+	var field protoreflect.FieldDescriptor
+	for i, name := range parts {
+		field = desc.Fields().ByName(protoreflect.Name(name))
+		if field == nil {
+			return nil, fmt.Errorf("unknown field %q", path)
+		}
+		if i < len(parts)-1 {
+			if field.Message() == nil {
+				return nil, fmt.Errorf("field %q is not a message", strings.Join(parts[:i+1], "."))
+			}
+			desc = field.Message()
+		}
+	}
+	return field, nil
+}
+
+// lookup returns the value at a field path and whether it is set. A path
+// through an unset message is unset.
+func lookup(m protoreflect.Message, path string) (protoreflect.Value, bool) {
+	parts := strings.Split(path, ".")
+	for _, name := range parts[:len(parts)-1] {
+		field := m.Descriptor().Fields().ByName(protoreflect.Name(name))
+		if !m.Has(field) {
+			return protoreflect.Value{}, false
+		}
+		m = m.Get(field).Message()
+	}
+	field := m.Descriptor().Fields().ByName(protoreflect.Name(parts[len(parts)-1]))
+	return m.Get(field), m.Has(field)
+}
+
+// sortedPaths orders field paths by the field numbers along each path
+func sortedPaths(desc protoreflect.MessageDescriptor, fields Fields) []string {
+	keys := make(map[string][]protoreflect.FieldNumber, len(fields))
+	paths := make([]string, 0, len(fields))
+	for path := range fields {
+		d := desc
+		for _, name := range strings.Split(path, ".") {
+			field := d.Fields().ByName(protoreflect.Name(name))
+			keys[path] = append(keys[path], field.Number())
+			d = field.Message()
+		}
+		paths = append(paths, path)
+	}
+	sort.Slice(paths, func(i, j int) bool {
+		return slices.Compare(keys[paths[i]], keys[paths[j]]) < 0
+	})
+	return paths
+}
+
+// displayName turns a field path into words for messages, e.g.
+// amount.minor_units -> amount minor units
+func displayName(field string) string {
+	return strings.NewReplacer(".", " ", "_", " ").Replace(field)
+}
+
+// Required rejects unset fields and empty strings
+func Required() Rule {
+	return func(field string, value protoreflect.Value, set bool) string {
+		if !set {
+			return displayName(field) + " is required"
+		}
+		if s, ok := value.Interface().(string); ok && strings.TrimSpace(s) == "" {
+			return displayName(field) + " is required"
+		}
+		return ""
+	}
+}
+
+// Length requires a string of min to max characters
+func Length(min, max int) Rule {
+	return func(field string, value protoreflect.Value, set bool) string {
+		if !set {
+			return ""
+		}
+		n := utf8.RuneCountInString(value.String())
+		if n < min || n > max {
+			return fmt.Sprintf("%s must be %d to %d characters", displayName(field), min, max)
+		}
+		return ""
+	}
+}
+
+// MaxLength limits a string to max characters
+func MaxLength(max int) Rule {
+	return func(field string, value protoreflect.Value, set bool) string {
+		if set && utf8.RuneCountInString(value.String()) > max {
+			return fmt.Sprintf("%s must be at most %d characters", displayName(field), max)
+		}
+		return ""
+	}
+}
+
+// Range requires an integer between min and max inclusive
+func Range(min, max int64) Rule {
+	return func(field string, value protoreflect.Value, set bool) string {
+		if !set {
+			return ""
+		}
+		n := value.Int()
+		if n < min || n > max {
+			return fmt.Sprintf("%s must be between %d and %d", displayName(field), min, max)
+		}
+		return ""
+	}
+}
+
+// Positive requires an integer greater than zero
+func Positive() Rule {
+	return func(field string, value protoreflect.Value, set bool) string {
+		if set && value.Int() <= 0 {
+			return displayName(field) + " must be greater than zero"
+		}
+		return ""
+	}
+}
+
+// OneOf requires one of the listed strings
+func OneOf(allowed ...string) Rule {
+	return func(field string, value protoreflect.Value, set bool) string {
+		if !set {
+			return ""
+		}
+		for _, a := range allowed {
+			if value.String() == a {
+				return ""
+			}
+		}
+		return fmt.Sprintf("%s must be one of %s", displayName(field), strings.Join(allowed, ", "))
+	}
+}
+
+// Email requires a bare email address such as name@example.com
+func Email() Rule {
+	return func(field string, value protoreflect.Value, set bool) string {
+		if !set {
+			return ""
+		}
+		addr, err := mail.ParseAddress(value.String())
+		if err != nil || addr.Address != value.String() || !strings.Contains(addr.Address[strings.LastIndex(addr.Address, "@"):], ".") {
+			return displayName(field) + " must be a valid email address"
+		}
+		return ""
+	}
+}
+
+// phonePattern accepts an optional + followed by 7 to 15 digits (E.164)
+var phonePattern = regexp.MustCompile(`^\+?[0-9]{7,15}$`)
+
+// Phone requires an international phone number such as +9779812345678
+func Phone() Rule {
+	return func(field string, value protoreflect.Value, set bool) string {
+		if set && !phonePattern.MatchString(value.String()) {
+			return displayName(field) + " must be 7 to 15 digits with an optional leading +"
+		}
+		return ""
+	}
+}
+
+// UUID requires a UUID such as the IDs the server issues
+func UUID() Rule {
+	return func(field string, value protoreflect.Value, set bool) string {
+		if !set {
+			return ""
+		}
+		if _, err := uuid.Parse(value.String()); err != nil {
+			return displayName(field) + " must be a valid ID"
+		}
+		return ""
+	}
+}
+
+// AccountNumber requires an account number with valid check digits, or a
+// legacy number. Spaces and hyphens between the digits are allowed.
+func AccountNumber() Rule {
+	return func(field string, value protoreflect.Value, set bool) string {
+		if set && accountnumber.Validate(value.String()) != nil && !accountnumber.IsLegacy(value.String()) {
+			return displayName(field) + " must be a valid account number"
+		}
+		return ""
+	}
+}
+
+// Date requires a calendar date in the form 2006-01-02
+func Date() Rule {
+	return func(field string, value protoreflect.Value, set bool) string {
+		if !set {
+			return ""
+		}
+		if _, err := time.Parse("2006-01-02", value.String()); err != nil {
+			return displayName(field) + " must be a date such as 2024-01-31"
+		}
+		return ""
+	}
+}
+
+// Currency requires an ISO 4217 code the bank holds
+func Currency() Rule {
+	return func(field string, value protoreflect.Value, set bool) string {
+		if set && !money.IsSupported(value.String()) {
+			return fmt.Sprintf("%s %q is not a supported currency", displayName(field), value.String())
+		}
+		return ""
+	}
+}
@@ -0,0 +1,59 @@
+package models
+
+import "time"
+
+// Account statuses. Accounts move between them along the transitions in
+// AccountTransitions; closed is final.
+const (
+	AccountStatusPending = "pending" // opened, awaiting approval
+	AccountStatusActive  = "active"
+	AccountStatusDormant = "dormant" // unused for a long time; credits only
+	AccountStatusFrozen  = "frozen"  // blocked by the bank; credits only
+	AccountStatusClosed  = "closed"
+)
+
+// AccountTransitions lists the statuses each status may change to
+var AccountTransitions = map[string][]string{
+	AccountStatusPending: {AccountStatusActive, AccountStatusClosed},
+	AccountStatusActive:  {AccountStatusDormant, AccountStatusFrozen, AccountStatusClosed},
+	AccountStatusDormant: {AccountStatusActive, AccountStatusFrozen, AccountStatusClosed},
+	AccountStatusFrozen:  {AccountStatusActive, AccountStatusClosed},
+	AccountStatusClosed:  {},
+}
+
+// Reason codes recorded with status changes
+const (
+	ReasonCustomerRequest = "CUSTOMER_REQUEST"
+	ReasonKYCCompleted    = "KYC_COMPLETED"
+	ReasonKYCExpired      = "KYC_EXPIRED"
+	ReasonInactivity      = "INACTIVITY"
+	ReasonFraudSuspected  = "FRAUD_SUSPECTED"
+	ReasonCourtOrder      = "COURT_ORDER"
+	ReasonReviewCompleted = "REVIEW_COMPLETED"
+	ReasonDeceased        = "DECEASED"
+	ReasonBankDecision    = "BANK_DECISION"
+)
+
+// StatusReasons lists the reason codes accepted for a change to each status
+var StatusReasons = map[string][]string{
+	AccountStatusActive:  {ReasonKYCCompleted, ReasonReviewCompleted, ReasonCustomerRequest},
+	AccountStatusDormant: {ReasonInactivity},
+	AccountStatusFrozen:  {ReasonFraudSuspected, ReasonCourtOrder, ReasonKYCExpired, ReasonCustomerRequest},
+	AccountStatusClosed:  {ReasonCustomerRequest, ReasonDeceased, ReasonBankDecision},
+}
+
+// AccountStatusChange records one status transition of an account
+type AccountStatusChange struct {
+	ID         string    `gorm:"primaryKey;column:change_id"`
+	AccountID  string    `gorm:"not null;index"`
+	FromStatus string    `gorm:"type:varchar(20);not null"`
+	ToStatus   string    `gorm:"type:varchar(20);not null"`
+	Reason     string    `gorm:"type:varchar(40);not null"` // one of StatusReasons[ToStatus]
+	Note       string    // free text from the person making the change
+	ChangedBy  string    `gorm:"not null"` // ID of the user who made the change
+	ChangedAt  time.Time `gorm:"not null"`
+}
+
+func (AccountStatusChange) TableName() string {
+	return "account_status_history"
+}
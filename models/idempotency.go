@@ -0,0 +1,22 @@
+package models
+
+import "time"
+
+// IdempotencyRecord stores the outcome of a mutating request so that a retry
+// with the same idempotency key replays it instead of running it again
+type IdempotencyRecord struct {
+	ID          string `gorm:"primaryKey;column:idempotency_id"`
+	UserID      string `gorm:"not null;uniqueIndex:idx_idempotency_scope_key"` // caller's user ID, or peer:<host> for public methods
+	Key         string `gorm:"column:idempotency_key;type:varchar(255);not null;uniqueIndex:idx_idempotency_scope_key"`
+	Method      string `gorm:"not null"`
+	RequestHash string `gorm:"type:char(64);not null"`
+	Response    []byte // serialized google.protobuf.Any, empty while the request is in flight or after it failed
+	Failure     []byte // serialized google.rpc.Status of a request that failed after it may have changed something
+	CompletedAt *time.Time
+	ExpiresAt   time.Time `gorm:"not null;index"` // end of the lease while in flight, end of the replay window once completed
+	CreatedAt   time.Time
+}
+
+func (IdempotencyRecord) TableName() string {
+	return "idempotency_records"
+}
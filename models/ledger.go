@@ -0,0 +1,60 @@
+package models
+
+import (
+	"strings"
+	"time"
+)
+
+// Journal entry types
+const (
+	EntryTypeDeposit    = "deposit"
+	EntryTypeWithdrawal = "withdrawal"
+	EntryTypeTransfer   = "transfer"
+	EntryTypeAdjustment = "adjustment"
+)
+
+// Internal ledger accounts are the bank's own side of a money movement.
+// They are not rows in the accounts table; their balance is the sum of
+// their postings.
+const (
+	InternalAccountPrefix = "internal:"
+	CashAccountID         = InternalAccountPrefix + "cash"
+)
+
+// IsInternalAccount reports whether the account ID refers to an internal ledger account
+func IsInternalAccount(accountID string) bool {
+	return strings.HasPrefix(accountID, InternalAccountPrefix)
+}
+
+// JournalEntry groups the postings of a single balanced money movement
+type JournalEntry struct {
+	ID          string `gorm:"primaryKey;column:entry_id"`
+	EntryType   string `gorm:"type:varchar(20);not null"` // deposit, withdrawal, transfer, adjustment
+	Currency    string `gorm:"type:varchar(3);not null;default:'NPR'"`
+	Reference   string `gorm:"index"`
+	Description string
+	PostedAt    time.Time `gorm:"not null;index"`
+
+	Postings []Posting `gorm:"foreignKey:EntryID;constraint:OnUpdate:CASCADE,OnDelete:RESTRICT"`
+
+	CreatedAt time.Time
+}
+
+func (JournalEntry) TableName() string {
+	return "journal_entries"
+}
+
+// Posting is one side of a journal entry against a single account.
+// Amounts are minor units of the entry currency; credits are positive and
+// debits are negative, so the postings of an entry always sum to zero.
+type Posting struct {
+	ID        string `gorm:"primaryKey;column:posting_id"`
+	EntryID   string `gorm:"not null;index"`
+	AccountID string `gorm:"not null;index"`
+	Amount    int64  `gorm:"column:amount_minor;not null;default:0"`
+	CreatedAt time.Time
+}
+
+func (Posting) TableName() string {
+	return "postings"
+}
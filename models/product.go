@@ -0,0 +1,104 @@
+package models
+
+import "time"
+
+// Codes of the products seeded into an empty catalog
+const (
+	ProductSavings      = "savings"
+	ProductCurrent      = "current"
+	ProductFixedDeposit = "fixed_deposit"
+)
+
+// AccountProduct is an entry of the account product catalog. Accounts
+// refer to their product by code through Account.AccountType.
+type AccountProduct struct {
+	Code                   string `gorm:"primaryKey;type:varchar(20)"`
+	Name                   string `gorm:"not null"`
+	Description            string
+	AllowWithdrawals       bool   `gorm:"not null"`                              // false blocks withdrawals and outgoing transfers
+	MaxAccountsPerCustomer int    `gorm:"not null;default:0"`                    // 0 means unlimited
+	Active                 bool   `gorm:"not null"`                              // inactive products cannot be used for new accounts
+	NumberPrefix           string `gorm:"type:varchar(2);not null;default:'00'"` // digits identifying the product in account numbers
+
+	Currencies []ProductCurrency `gorm:"foreignKey:ProductCode;constraint:OnUpdate:CASCADE,OnDelete:CASCADE"`
+
+	CreatedAt time.Time
+	UpdatedAt time.Time
+}
+
+func (AccountProduct) TableName() string {
+	return "account_products"
+}
+
+// Currency returns the product's settings for a currency, if it is offered in it
+func (p *AccountProduct) Currency(code string) (*ProductCurrency, bool) {
+	for i := range p.Currencies {
+		if p.Currencies[i].Currency == code {
+			return &p.Currencies[i], true
+		}
+	}
+	return nil, false
+}
+
+// ProductCurrency is a currency an account product is offered in
+type ProductCurrency struct {
+	ProductCode       string `gorm:"primaryKey;type:varchar(20)"`
+	Currency          string `gorm:"primaryKey;type:varchar(3)"`
+	MinOpeningBalance int64  `gorm:"column:min_opening_balance_minor;not null;default:0"` // minor units of Currency
+}
+
+func (ProductCurrency) TableName() string {
+	return "product_currencies"
+}
+
+// DefaultProducts returns the catalog a new installation starts with.
+// Amounts are minor units.
+func DefaultProducts() []AccountProduct {
+	now := time.Now()
+	return []AccountProduct{
+		{
+			Code:             ProductSavings,
+			NumberPrefix:     "10",
+			Name:             "Savings",
+			Description:      "Interest-bearing savings account",
+			AllowWithdrawals: true,
+			Active:           true,
+			Currencies: []ProductCurrency{
+				{ProductCode: ProductSavings, Currency: "NPR"},
+				{ProductCode: ProductSavings, Currency: "INR"},
+				{ProductCode: ProductSavings, Currency: "USD"},
+				{ProductCode: ProductSavings, Currency: "EUR"},
+			},
+			CreatedAt: now,
+			UpdatedAt: now,
+		},
+		{
+			Code:             ProductCurrent,
+			NumberPrefix:     "20",
+			Name:             "Current",
+			Description:      "Current (checking) account for everyday payments",
+			AllowWithdrawals: true,
+			Active:           true,
+			Currencies: []ProductCurrency{
+				{ProductCode: ProductCurrent, Currency: "NPR", MinOpeningBalance: 1000_00},
+				{ProductCode: ProductCurrent, Currency: "USD", MinOpeningBalance: 100_00},
+			},
+			CreatedAt: now,
+			UpdatedAt: now,
+		},
+		{
+			Code:                   ProductFixedDeposit,
+			NumberPrefix:           "30",
+			Name:                   "Fixed deposit",
+			Description:            "Term deposit; money stays in the account until maturity",
+			AllowWithdrawals:       false,
+			MaxAccountsPerCustomer: 10,
+			Active:                 true,
+			Currencies: []ProductCurrency{
+				{ProductCode: ProductFixedDeposit, Currency: "NPR", MinOpeningBalance: 10000_00},
+			},
+			CreatedAt: now,
+			UpdatedAt: now,
+		},
+	}
+}
@@ -0,0 +1,32 @@
+package models
+
+import "time"
+
+// Session is a login session. Every refresh token issued from one login
+// belongs to the same session, so revoking it ends the whole token family.
+type Session struct {
+	ID             string    `gorm:"primaryKey;column:session_id"`
+	UserID         string    `gorm:"not null;index"`
+	RefreshTokenID string    `gorm:"not null"` // jti of the only refresh token that may still be used
+	ExpiresAt      time.Time `gorm:"not null"`
+	RevokedAt      *time.Time
+	RevokedReason  string
+	CreatedAt      time.Time
+	UpdatedAt      time.Time
+}
+
+func (Session) TableName() string {
+	return "sessions"
+}
+
+// RevokedToken is an access token that was revoked before it expired
+type RevokedToken struct {
+	TokenID   string    `gorm:"primaryKey;column:token_id"` // jti claim
+	UserID    string    `gorm:"not null;index"`
+	ExpiresAt time.Time `gorm:"not null;index"`
+	RevokedAt time.Time `gorm:"not null"`
+}
+
+func (RevokedToken) TableName() string {
+	return "revoked_tokens"
+}
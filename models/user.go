@@ -7,7 +7,7 @@ import (
 )
 
 type Customer struct {
-	ID        string `gorm:"primaryKey;column:customer_id"`
+	ID        string `gorm:"primaryKey;column:customer_id;index:idx_customers_created_at_id,priority:2"`
 	FirstName string `gorm:"not null"`
 	LastName  string `gorm:"not null"`
 	Address   string
@@ -16,7 +16,7 @@ type Customer struct {
 
 	Accounts []Account `gorm:"foreignKey:CustomerID;constraint:OnUpdate:CASCADE,OnDelete:RESTRICT"`
 
-	CreatedAt time.Time
+	CreatedAt time.Time `gorm:"index:idx_customers_created_at_id,priority:1"` // list order, with ID
 	UpdatedAt time.Time
 	DeletedAt gorm.DeletedAt `gorm:"index"`
 }
@@ -26,17 +26,18 @@ func (Customer) TableName() string {
 }
 
 type Account struct {
-	ID            string    `gorm:"primaryKey;column:account_id"`
+	ID            string    `gorm:"primaryKey;column:account_id;index:idx_accounts_created_at_id,priority:2"`
 	AccountNumber string    `gorm:"uniqueIndex;not null"`
-	Status        string    `gorm:"type:varchar(20);not null"` // active, frozen, closed
-	Balance       float64   `gorm:"type:numeric(18,2);not null;default:0"`
+	IBAN          string    `gorm:"column:iban;type:varchar(34)"`            // empty when IBANs are not configured
+	Status        string    `gorm:"type:varchar(20);not null"`               // one of the AccountStatus constants
+	Balance       int64     `gorm:"column:balance_minor;not null;default:0"` // minor units of Currency, cached sum of postings
 	OpenedAt      time.Time `gorm:"not null"`
 
-	CustomerID  string   `gorm:"not null"`
-	Customer    Customer `gorm:"foreignKey:CustomerID"`
-	Currency    string   `gorm:"type:varchar(3);not null;default:'NPR'"`
-	AccountType string   `gorm:"type:varchar(20);not null;default:'savings'"`
-	CreatedAt   time.Time
+	CustomerID  string    `gorm:"not null"`
+	Customer    Customer  `gorm:"foreignKey:CustomerID"`
+	Currency    string    `gorm:"type:varchar(3);not null;default:'NPR'"`
+	AccountType string    `gorm:"type:varchar(20);not null;default:'savings'"`
+	CreatedAt   time.Time `gorm:"index:idx_accounts_created_at_id,priority:1"` // list order, with ID
 	UpdatedAt   time.Time
 	DeletedAt   gorm.DeletedAt `gorm:"index"`
 }
@@ -45,13 +46,35 @@ func (Account) TableName() string {
 	return "accounts"
 }
 
+// User roles
+const (
+	RoleAdmin    = "admin"    // full access, including deletes and role management
+	RoleTeller   = "teller"   // serves customers and moves money
+	RoleAuditor  = "auditor"  // read-only access to customers and accounts
+	RoleCustomer = "customer" // end user
+)
+
+// IsValidRole reports whether role is one of the known user roles
+func IsValidRole(role string) bool {
+	switch role {
+	case RoleAdmin, RoleTeller, RoleAuditor, RoleCustomer:
+		return true
+	}
+	return false
+}
+
 // User represents the authentication user
 type User struct {
-	ID        string `gorm:"primaryKey;column:user_id"`
-	Username  string `gorm:"uniqueIndex;not null"`
-	Password  string `gorm:"not null"` // hashed password
-	Email     string `gorm:"uniqueIndex;not null"`
-	IsActive  bool   `gorm:"default:true"`
+	ID       string `gorm:"primaryKey;column:user_id"`
+	Username string `gorm:"uniqueIndex;not null"`
+	Password string `gorm:"not null"` // hashed password
+	Email    string `gorm:"uniqueIndex;not null"`
+	Role     string `gorm:"type:varchar(20);not null;default:'customer'"`
+	IsActive bool   `gorm:"default:true"`
+
+	CustomerID *string   `gorm:"index"` // bank customer this login acts for, if any
+	Customer   *Customer `gorm:"foreignKey:CustomerID;constraint:OnUpdate:CASCADE,OnDelete:SET NULL"`
+
 	CreatedAt time.Time
 	UpdatedAt time.Time
 	DeletedAt gorm.DeletedAt `gorm:"index"`
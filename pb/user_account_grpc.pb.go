@@ -19,16 +19,23 @@ import (
 const _ = grpc.SupportPackageIsVersion7
 
 const (
-	AccountService_CreateUser_FullMethodName    = "/grpc_crud.AccountService/CreateUser"
-	AccountService_GetUser_FullMethodName       = "/grpc_crud.AccountService/GetUser"
-	AccountService_UpdateUser_FullMethodName    = "/grpc_crud.AccountService/UpdateUser"
-	AccountService_DeleteUser_FullMethodName    = "/grpc_crud.AccountService/DeleteUser"
-	AccountService_ListUsers_FullMethodName     = "/grpc_crud.AccountService/ListUsers"
-	AccountService_CreateAccount_FullMethodName = "/grpc_crud.AccountService/CreateAccount"
-	AccountService_GetAccount_FullMethodName    = "/grpc_crud.AccountService/GetAccount"
-	AccountService_UpdateAccount_FullMethodName = "/grpc_crud.AccountService/UpdateAccount"
-	AccountService_DeleteAccount_FullMethodName = "/grpc_crud.AccountService/DeleteAccount"
-	AccountService_ListAccounts_FullMethodName  = "/grpc_crud.AccountService/ListAccounts"
+	AccountService_CreateUser_FullMethodName              = "/grpc_crud.AccountService/CreateUser"
+	AccountService_GetUser_FullMethodName                 = "/grpc_crud.AccountService/GetUser"
+	AccountService_UpdateUser_FullMethodName              = "/grpc_crud.AccountService/UpdateUser"
+	AccountService_DeleteUser_FullMethodName              = "/grpc_crud.AccountService/DeleteUser"
+	AccountService_ListUsers_FullMethodName               = "/grpc_crud.AccountService/ListUsers"
+	AccountService_CreateAccount_FullMethodName           = "/grpc_crud.AccountService/CreateAccount"
+	AccountService_GetAccount_FullMethodName              = "/grpc_crud.AccountService/GetAccount"
+	AccountService_UpdateAccount_FullMethodName           = "/grpc_crud.AccountService/UpdateAccount"
+	AccountService_DeleteAccount_FullMethodName           = "/grpc_crud.AccountService/DeleteAccount"
+	AccountService_ListAccounts_FullMethodName            = "/grpc_crud.AccountService/ListAccounts"
+	AccountService_Deposit_FullMethodName                 = "/grpc_crud.AccountService/Deposit"
+	AccountService_Withdraw_FullMethodName                = "/grpc_crud.AccountService/Withdraw"
+	AccountService_Transfer_FullMethodName                = "/grpc_crud.AccountService/Transfer"
+	AccountService_FreezeAccount_FullMethodName           = "/grpc_crud.AccountService/FreezeAccount"
+	AccountService_UnfreezeAccount_FullMethodName         = "/grpc_crud.AccountService/UnfreezeAccount"
+	AccountService_CloseAccount_FullMethodName            = "/grpc_crud.AccountService/CloseAccount"
+	AccountService_GetAccountStatusHistory_FullMethodName = "/grpc_crud.AccountService/GetAccountStatusHistory"
 )
 
 // AccountServiceClient is the client API for AccountService service.
@@ -55,6 +62,20 @@ type AccountServiceClient interface {
 	DeleteAccount(ctx context.Context, in *DeleteAccountRequest, opts ...grpc.CallOption) (*DeleteAccountResponse, error)
 	// list all accounts
 	ListAccounts(ctx context.Context, in *ListAccountRequest, opts ...grpc.CallOption) (*ListAccountResponse, error)
+	// deposit money into an account
+	Deposit(ctx context.Context, in *DepositRequest, opts ...grpc.CallOption) (*DepositResponse, error)
+	// withdraw money from an account
+	Withdraw(ctx context.Context, in *WithdrawRequest, opts ...grpc.CallOption) (*WithdrawResponse, error)
+	// transfer money between two accounts
+	Transfer(ctx context.Context, in *TransferRequest, opts ...grpc.CallOption) (*TransferResponse, error)
+	// block money leaving an account
+	FreezeAccount(ctx context.Context, in *FreezeAccountRequest, opts ...grpc.CallOption) (*FreezeAccountResponse, error)
+	// return a frozen account to active
+	UnfreezeAccount(ctx context.Context, in *UnfreezeAccountRequest, opts ...grpc.CallOption) (*UnfreezeAccountResponse, error)
+	// close an account; its balance must be zero
+	CloseAccount(ctx context.Context, in *CloseAccountRequest, opts ...grpc.CallOption) (*CloseAccountResponse, error)
+	// list the status changes of an account
+	GetAccountStatusHistory(ctx context.Context, in *GetAccountStatusHistoryRequest, opts ...grpc.CallOption) (*GetAccountStatusHistoryResponse, error)
 }
 
 type accountServiceClient struct {
@@ -155,6 +176,69 @@ func (c *accountServiceClient) ListAccounts(ctx context.Context, in *ListAccount
 	return out, nil
 }
 
+func (c *accountServiceClient) Deposit(ctx context.Context, in *DepositRequest, opts ...grpc.CallOption) (*DepositResponse, error) {
+	out := new(DepositResponse)
+	err := c.cc.Invoke(ctx, AccountService_Deposit_FullMethodName, in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *accountServiceClient) Withdraw(ctx context.Context, in *WithdrawRequest, opts ...grpc.CallOption) (*WithdrawResponse, error) {
+	out := new(WithdrawResponse)
+	err := c.cc.Invoke(ctx, AccountService_Withdraw_FullMethodName, in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *accountServiceClient) Transfer(ctx context.Context, in *TransferRequest, opts ...grpc.CallOption) (*TransferResponse, error) {
+	out := new(TransferResponse)
+	err := c.cc.Invoke(ctx, AccountService_Transfer_FullMethodName, in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *accountServiceClient) FreezeAccount(ctx context.Context, in *FreezeAccountRequest, opts ...grpc.CallOption) (*FreezeAccountResponse, error) {
+	out := new(FreezeAccountResponse)
+	err := c.cc.Invoke(ctx, AccountService_FreezeAccount_FullMethodName, in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *accountServiceClient) UnfreezeAccount(ctx context.Context, in *UnfreezeAccountRequest, opts ...grpc.CallOption) (*UnfreezeAccountResponse, error) {
+	out := new(UnfreezeAccountResponse)
+	err := c.cc.Invoke(ctx, AccountService_UnfreezeAccount_FullMethodName, in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *accountServiceClient) CloseAccount(ctx context.Context, in *CloseAccountRequest, opts ...grpc.CallOption) (*CloseAccountResponse, error) {
+	out := new(CloseAccountResponse)
+	err := c.cc.Invoke(ctx, AccountService_CloseAccount_FullMethodName, in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *accountServiceClient) GetAccountStatusHistory(ctx context.Context, in *GetAccountStatusHistoryRequest, opts ...grpc.CallOption) (*GetAccountStatusHistoryResponse, error) {
+	out := new(GetAccountStatusHistoryResponse)
+	err := c.cc.Invoke(ctx, AccountService_GetAccountStatusHistory_FullMethodName, in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
 // AccountServiceServer is the server API for AccountService service.
 // All implementations must embed UnimplementedAccountServiceServer
 // for forward compatibility
@@ -179,6 +263,20 @@ type AccountServiceServer interface {
 	DeleteAccount(context.Context, *DeleteAccountRequest) (*DeleteAccountResponse, error)
 	// list all accounts
 	ListAccounts(context.Context, *ListAccountRequest) (*ListAccountResponse, error)
+	// deposit money into an account
+	Deposit(context.Context, *DepositRequest) (*DepositResponse, error)
+	// withdraw money from an account
+	Withdraw(context.Context, *WithdrawRequest) (*WithdrawResponse, error)
+	// transfer money between two accounts
+	Transfer(context.Context, *TransferRequest) (*TransferResponse, error)
+	// block money leaving an account
+	FreezeAccount(context.Context, *FreezeAccountRequest) (*FreezeAccountResponse, error)
+	// return a frozen account to active
+	UnfreezeAccount(context.Context, *UnfreezeAccountRequest) (*UnfreezeAccountResponse, error)
+	// close an account; its balance must be zero
+	CloseAccount(context.Context, *CloseAccountRequest) (*CloseAccountResponse, error)
+	// list the status changes of an account
+	GetAccountStatusHistory(context.Context, *GetAccountStatusHistoryRequest) (*GetAccountStatusHistoryResponse, error)
 	mustEmbedUnimplementedAccountServiceServer()
 }
 
@@ -216,6 +314,27 @@ func (UnimplementedAccountServiceServer) DeleteAccount(context.Context, *DeleteA
 func (UnimplementedAccountServiceServer) ListAccounts(context.Context, *ListAccountRequest) (*ListAccountResponse, error) {
 	return nil, status.Errorf(codes.Unimplemented, "method ListAccounts not implemented")
 }
+func (UnimplementedAccountServiceServer) Deposit(context.Context, *DepositRequest) (*DepositResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method Deposit not implemented")
+}
+func (UnimplementedAccountServiceServer) Withdraw(context.Context, *WithdrawRequest) (*WithdrawResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method Withdraw not implemented")
+}
+func (UnimplementedAccountServiceServer) Transfer(context.Context, *TransferRequest) (*TransferResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method Transfer not implemented")
+}
+func (UnimplementedAccountServiceServer) FreezeAccount(context.Context, *FreezeAccountRequest) (*FreezeAccountResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method FreezeAccount not implemented")
+}
+func (UnimplementedAccountServiceServer) UnfreezeAccount(context.Context, *UnfreezeAccountRequest) (*UnfreezeAccountResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method UnfreezeAccount not implemented")
+}
+func (UnimplementedAccountServiceServer) CloseAccount(context.Context, *CloseAccountRequest) (*CloseAccountResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method CloseAccount not implemented")
+}
+func (UnimplementedAccountServiceServer) GetAccountStatusHistory(context.Context, *GetAccountStatusHistoryRequest) (*GetAccountStatusHistoryResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetAccountStatusHistory not implemented")
+}
 func (UnimplementedAccountServiceServer) mustEmbedUnimplementedAccountServiceServer() {}
 
 // UnsafeAccountServiceServer may be embedded to opt out of forward compatibility for this service.
@@ -409,6 +528,132 @@ func _AccountService_ListAccounts_Handler(srv interface{}, ctx context.Context,
 	return interceptor(ctx, in, info, handler)
 }
 
+func _AccountService_Deposit_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(DepositRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(AccountServiceServer).Deposit(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: AccountService_Deposit_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(AccountServiceServer).Deposit(ctx, req.(*DepositRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _AccountService_Withdraw_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(WithdrawRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(AccountServiceServer).Withdraw(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: AccountService_Withdraw_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(AccountServiceServer).Withdraw(ctx, req.(*WithdrawRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _AccountService_Transfer_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(TransferRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(AccountServiceServer).Transfer(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: AccountService_Transfer_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(AccountServiceServer).Transfer(ctx, req.(*TransferRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _AccountService_FreezeAccount_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(FreezeAccountRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(AccountServiceServer).FreezeAccount(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: AccountService_FreezeAccount_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(AccountServiceServer).FreezeAccount(ctx, req.(*FreezeAccountRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _AccountService_UnfreezeAccount_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(UnfreezeAccountRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(AccountServiceServer).UnfreezeAccount(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: AccountService_UnfreezeAccount_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(AccountServiceServer).UnfreezeAccount(ctx, req.(*UnfreezeAccountRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _AccountService_CloseAccount_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(CloseAccountRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(AccountServiceServer).CloseAccount(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: AccountService_CloseAccount_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(AccountServiceServer).CloseAccount(ctx, req.(*CloseAccountRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _AccountService_GetAccountStatusHistory_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(GetAccountStatusHistoryRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(AccountServiceServer).GetAccountStatusHistory(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: AccountService_GetAccountStatusHistory_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(AccountServiceServer).GetAccountStatusHistory(ctx, req.(*GetAccountStatusHistoryRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
 // AccountService_ServiceDesc is the grpc.ServiceDesc for AccountService service.
 // It's only intended for direct use with grpc.RegisterService,
 // and not to be introspected or modified (even as a copy)
@@ -456,7 +701,474 @@ var AccountService_ServiceDesc = grpc.ServiceDesc{
 			MethodName: "ListAccounts",
 			Handler:    _AccountService_ListAccounts_Handler,
 		},
+		{
+			MethodName: "Deposit",
+			Handler:    _AccountService_Deposit_Handler,
+		},
+		{
+			MethodName: "Withdraw",
+			Handler:    _AccountService_Withdraw_Handler,
+		},
+		{
+			MethodName: "Transfer",
+			Handler:    _AccountService_Transfer_Handler,
+		},
+		{
+			MethodName: "FreezeAccount",
+			Handler:    _AccountService_FreezeAccount_Handler,
+		},
+		{
+			MethodName: "UnfreezeAccount",
+			Handler:    _AccountService_UnfreezeAccount_Handler,
+		},
+		{
+			MethodName: "CloseAccount",
+			Handler:    _AccountService_CloseAccount_Handler,
+		},
+		{
+			MethodName: "GetAccountStatusHistory",
+			Handler:    _AccountService_GetAccountStatusHistory_Handler,
+		},
 	},
 	Streams:  []grpc.StreamDesc{},
 	Metadata: "user_account.proto",
 }
+
+const (
+	ProductService_CreateProduct_FullMethodName = "/grpc_crud.ProductService/CreateProduct"
+	ProductService_GetProduct_FullMethodName    = "/grpc_crud.ProductService/GetProduct"
+	ProductService_UpdateProduct_FullMethodName = "/grpc_crud.ProductService/UpdateProduct"
+	ProductService_ListProducts_FullMethodName  = "/grpc_crud.ProductService/ListProducts"
+)
+
+// ProductServiceClient is the client API for ProductService service.
+//
+// For semantics around ctx use and closing/ending streaming RPCs, please refer to https://pkg.go.dev/google.golang.org/grpc/?tab=doc#ClientConn.NewStream.
+type ProductServiceClient interface {
+	// create a new account product
+	CreateProduct(ctx context.Context, in *CreateProductRequest, opts ...grpc.CallOption) (*CreateProductResponse, error)
+	// retrieve an account product by code
+	GetProduct(ctx context.Context, in *GetProductRequest, opts ...grpc.CallOption) (*AccountProduct, error)
+	// replace the settings of an account product
+	UpdateProduct(ctx context.Context, in *UpdateProductRequest, opts ...grpc.CallOption) (*UpdateProductResponse, error)
+	// list account products
+	ListProducts(ctx context.Context, in *ListProductsRequest, opts ...grpc.CallOption) (*ListProductsResponse, error)
+}
+
+type productServiceClient struct {
+	cc grpc.ClientConnInterface
+}
+
+func NewProductServiceClient(cc grpc.ClientConnInterface) ProductServiceClient {
+	return &productServiceClient{cc}
+}
+
+func (c *productServiceClient) CreateProduct(ctx context.Context, in *CreateProductRequest, opts ...grpc.CallOption) (*CreateProductResponse, error) {
+	out := new(CreateProductResponse)
+	err := c.cc.Invoke(ctx, ProductService_CreateProduct_FullMethodName, in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *productServiceClient) GetProduct(ctx context.Context, in *GetProductRequest, opts ...grpc.CallOption) (*AccountProduct, error) {
+	out := new(AccountProduct)
+	err := c.cc.Invoke(ctx, ProductService_GetProduct_FullMethodName, in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *productServiceClient) UpdateProduct(ctx context.Context, in *UpdateProductRequest, opts ...grpc.CallOption) (*UpdateProductResponse, error) {
+	out := new(UpdateProductResponse)
+	err := c.cc.Invoke(ctx, ProductService_UpdateProduct_FullMethodName, in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *productServiceClient) ListProducts(ctx context.Context, in *ListProductsRequest, opts ...grpc.CallOption) (*ListProductsResponse, error) {
+	out := new(ListProductsResponse)
+	err := c.cc.Invoke(ctx, ProductService_ListProducts_FullMethodName, in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+// ProductServiceServer is the server API for ProductService service.
+// All implementations must embed UnimplementedProductServiceServer
+// for forward compatibility
+type ProductServiceServer interface {
+	// create a new account product
+	CreateProduct(context.Context, *CreateProductRequest) (*CreateProductResponse, error)
+	// retrieve an account product by code
+	GetProduct(context.Context, *GetProductRequest) (*AccountProduct, error)
+	// replace the settings of an account product
+	UpdateProduct(context.Context, *UpdateProductRequest) (*UpdateProductResponse, error)
+	// list account products
+	ListProducts(context.Context, *ListProductsRequest) (*ListProductsResponse, error)
+	mustEmbedUnimplementedProductServiceServer()
+}
+
+// UnimplementedProductServiceServer must be embedded to have forward compatible implementations.
+type UnimplementedProductServiceServer struct {
+}
+
+func (UnimplementedProductServiceServer) CreateProduct(context.Context, *CreateProductRequest) (*CreateProductResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method CreateProduct not implemented")
+}
+func (UnimplementedProductServiceServer) GetProduct(context.Context, *GetProductRequest) (*AccountProduct, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetProduct not implemented")
+}
+func (UnimplementedProductServiceServer) UpdateProduct(context.Context, *UpdateProductRequest) (*UpdateProductResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method UpdateProduct not implemented")
+}
+func (UnimplementedProductServiceServer) ListProducts(context.Context, *ListProductsRequest) (*ListProductsResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method ListProducts not implemented")
+}
+func (UnimplementedProductServiceServer) mustEmbedUnimplementedProductServiceServer() {}
+
+// UnsafeProductServiceServer may be embedded to opt out of forward compatibility for this service.
+// Use of this interface is not recommended, as added methods to ProductServiceServer will
+// result in compilation errors.
+type UnsafeProductServiceServer interface {
+	mustEmbedUnimplementedProductServiceServer()
+}
+
+func RegisterProductServiceServer(s grpc.ServiceRegistrar, srv ProductServiceServer) {
+	s.RegisterService(&ProductService_ServiceDesc, srv)
+}
+
+func _ProductService_CreateProduct_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(CreateProductRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(ProductServiceServer).CreateProduct(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: ProductService_CreateProduct_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(ProductServiceServer).CreateProduct(ctx, req.(*CreateProductRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _ProductService_GetProduct_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(GetProductRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(ProductServiceServer).GetProduct(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: ProductService_GetProduct_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(ProductServiceServer).GetProduct(ctx, req.(*GetProductRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _ProductService_UpdateProduct_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(UpdateProductRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(ProductServiceServer).UpdateProduct(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: ProductService_UpdateProduct_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(ProductServiceServer).UpdateProduct(ctx, req.(*UpdateProductRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _ProductService_ListProducts_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ListProductsRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(ProductServiceServer).ListProducts(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: ProductService_ListProducts_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(ProductServiceServer).ListProducts(ctx, req.(*ListProductsRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+// ProductService_ServiceDesc is the grpc.ServiceDesc for ProductService service.
+// It's only intended for direct use with grpc.RegisterService,
+// and not to be introspected or modified (even as a copy)
+var ProductService_ServiceDesc = grpc.ServiceDesc{
+	ServiceName: "grpc_crud.ProductService",
+	HandlerType: (*ProductServiceServer)(nil),
+	Methods: []grpc.MethodDesc{
+		{
+			MethodName: "CreateProduct",
+			Handler:    _ProductService_CreateProduct_Handler,
+		},
+		{
+			MethodName: "GetProduct",
+			Handler:    _ProductService_GetProduct_Handler,
+		},
+		{
+			MethodName: "UpdateProduct",
+			Handler:    _ProductService_UpdateProduct_Handler,
+		},
+		{
+			MethodName: "ListProducts",
+			Handler:    _ProductService_ListProducts_Handler,
+		},
+	},
+	Streams:  []grpc.StreamDesc{},
+	Metadata: "user_account.proto",
+}
+
+const (
+	AccountStatementService_GetStatement_FullMethodName    = "/grpc_crud.AccountStatementService/GetStatement"
+	AccountStatementService_StreamStatement_FullMethodName = "/grpc_crud.AccountStatementService/StreamStatement"
+	AccountStatementService_ExportStatement_FullMethodName = "/grpc_crud.AccountStatementService/ExportStatement"
+)
+
+// AccountStatementServiceClient is the client API for AccountStatementService service.
+//
+// For semantics around ctx use and closing/ending streaming RPCs, please refer to https://pkg.go.dev/google.golang.org/grpc/?tab=doc#ClientConn.NewStream.
+type AccountStatementServiceClient interface {
+	// statement of an account for a date range, one page of lines at a time
+	GetStatement(ctx context.Context, in *GetStatementRequest, opts ...grpc.CallOption) (*GetStatementResponse, error)
+	// statement of an account for a date range, streamed in chunks; suits
+	// long ranges. The first chunk carries the summary.
+	StreamStatement(ctx context.Context, in *StreamStatementRequest, opts ...grpc.CallOption) (AccountStatementService_StreamStatementClient, error)
+	// render a statement as a CSV or PDF file, streamed in chunks. The first
+	// chunk carries the content type and file name.
+	ExportStatement(ctx context.Context, in *ExportStatementRequest, opts ...grpc.CallOption) (AccountStatementService_ExportStatementClient, error)
+}
+
+type accountStatementServiceClient struct {
+	cc grpc.ClientConnInterface
+}
+
+func NewAccountStatementServiceClient(cc grpc.ClientConnInterface) AccountStatementServiceClient {
+	return &accountStatementServiceClient{cc}
+}
+
+func (c *accountStatementServiceClient) GetStatement(ctx context.Context, in *GetStatementRequest, opts ...grpc.CallOption) (*GetStatementResponse, error) {
+	out := new(GetStatementResponse)
+	err := c.cc.Invoke(ctx, AccountStatementService_GetStatement_FullMethodName, in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *accountStatementServiceClient) StreamStatement(ctx context.Context, in *StreamStatementRequest, opts ...grpc.CallOption) (AccountStatementService_StreamStatementClient, error) {
+	stream, err := c.cc.NewStream(ctx, &AccountStatementService_ServiceDesc.Streams[0], AccountStatementService_StreamStatement_FullMethodName, opts...)
+	if err != nil {
+		return nil, err
+	}
+	x := &accountStatementServiceStreamStatementClient{stream}
+	if err := x.ClientStream.SendMsg(in); err != nil {
+		return nil, err
+	}
+	if err := x.ClientStream.CloseSend(); err != nil {
+		return nil, err
+	}
+	return x, nil
+}
+
+type AccountStatementService_StreamStatementClient interface {
+	Recv() (*StatementChunk, error)
+	grpc.ClientStream
+}
+
+type accountStatementServiceStreamStatementClient struct {
+	grpc.ClientStream
+}
+
+func (x *accountStatementServiceStreamStatementClient) Recv() (*StatementChunk, error) {
+	m := new(StatementChunk)
+	if err := x.ClientStream.RecvMsg(m); err != nil {
+		return nil, err
+	}
+	return m, nil
+}
+
+func (c *accountStatementServiceClient) ExportStatement(ctx context.Context, in *ExportStatementRequest, opts ...grpc.CallOption) (AccountStatementService_ExportStatementClient, error) {
+	stream, err := c.cc.NewStream(ctx, &AccountStatementService_ServiceDesc.Streams[1], AccountStatementService_ExportStatement_FullMethodName, opts...)
+	if err != nil {
+		return nil, err
+	}
+	x := &accountStatementServiceExportStatementClient{stream}
+	if err := x.ClientStream.SendMsg(in); err != nil {
+		return nil, err
+	}
+	if err := x.ClientStream.CloseSend(); err != nil {
+		return nil, err
+	}
+	return x, nil
+}
+
+type AccountStatementService_ExportStatementClient interface {
+	Recv() (*StatementFileChunk, error)
+	grpc.ClientStream
+}
+
+type accountStatementServiceExportStatementClient struct {
+	grpc.ClientStream
+}
+
+func (x *accountStatementServiceExportStatementClient) Recv() (*StatementFileChunk, error) {
+	m := new(StatementFileChunk)
+	if err := x.ClientStream.RecvMsg(m); err != nil {
+		return nil, err
+	}
+	return m, nil
+}
+
+// AccountStatementServiceServer is the server API for AccountStatementService service.
+// All implementations must embed UnimplementedAccountStatementServiceServer
+// for forward compatibility
+type AccountStatementServiceServer interface {
+	// statement of an account for a date range, one page of lines at a time
+	GetStatement(context.Context, *GetStatementRequest) (*GetStatementResponse, error)
+	// statement of an account for a date range, streamed in chunks; suits
+	// long ranges. The first chunk carries the summary.
+	StreamStatement(*StreamStatementRequest, AccountStatementService_StreamStatementServer) error
+	// render a statement as a CSV or PDF file, streamed in chunks. The first
+	// chunk carries the content type and file name.
+	ExportStatement(*ExportStatementRequest, AccountStatementService_ExportStatementServer) error
+	mustEmbedUnimplementedAccountStatementServiceServer()
+}
+
+// UnimplementedAccountStatementServiceServer must be embedded to have forward compatible implementations.
+type UnimplementedAccountStatementServiceServer struct {
+}
+
+func (UnimplementedAccountStatementServiceServer) GetStatement(context.Context, *GetStatementRequest) (*GetStatementResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetStatement not implemented")
+}
+func (UnimplementedAccountStatementServiceServer) StreamStatement(*StreamStatementRequest, AccountStatementService_StreamStatementServer) error {
+	return status.Errorf(codes.Unimplemented, "method StreamStatement not implemented")
+}
+func (UnimplementedAccountStatementServiceServer) ExportStatement(*ExportStatementRequest, AccountStatementService_ExportStatementServer) error {
+	return status.Errorf(codes.Unimplemented, "method ExportStatement not implemented")
+}
+func (UnimplementedAccountStatementServiceServer) mustEmbedUnimplementedAccountStatementServiceServer() {
+}
+
+// UnsafeAccountStatementServiceServer may be embedded to opt out of forward compatibility for this service.
+// Use of this interface is not recommended, as added methods to AccountStatementServiceServer will
+// result in compilation errors.
+type UnsafeAccountStatementServiceServer interface {
+	mustEmbedUnimplementedAccountStatementServiceServer()
+}
+
+func RegisterAccountStatementServiceServer(s grpc.ServiceRegistrar, srv AccountStatementServiceServer) {
+	s.RegisterService(&AccountStatementService_ServiceDesc, srv)
+}
+
+func _AccountStatementService_GetStatement_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(GetStatementRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(AccountStatementServiceServer).GetStatement(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: AccountStatementService_GetStatement_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(AccountStatementServiceServer).GetStatement(ctx, req.(*GetStatementRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _AccountStatementService_StreamStatement_Handler(srv interface{}, stream grpc.ServerStream) error {
+	m := new(StreamStatementRequest)
+	if err := stream.RecvMsg(m); err != nil {
+		return err
+	}
+	return srv.(AccountStatementServiceServer).StreamStatement(m, &accountStatementServiceStreamStatementServer{stream})
+}
+
+type AccountStatementService_StreamStatementServer interface {
+	Send(*StatementChunk) error
+	grpc.ServerStream
+}
+
+type accountStatementServiceStreamStatementServer struct {
+	grpc.ServerStream
+}
+
+func (x *accountStatementServiceStreamStatementServer) Send(m *StatementChunk) error {
+	return x.ServerStream.SendMsg(m)
+}
+
+func _AccountStatementService_ExportStatement_Handler(srv interface{}, stream grpc.ServerStream) error {
+	m := new(ExportStatementRequest)
+	if err := stream.RecvMsg(m); err != nil {
+		return err
+	}
+	return srv.(AccountStatementServiceServer).ExportStatement(m, &accountStatementServiceExportStatementServer{stream})
+}
+
+type AccountStatementService_ExportStatementServer interface {
+	Send(*StatementFileChunk) error
+	grpc.ServerStream
+}
+
+type accountStatementServiceExportStatementServer struct {
+	grpc.ServerStream
+}
+
+func (x *accountStatementServiceExportStatementServer) Send(m *StatementFileChunk) error {
+	return x.ServerStream.SendMsg(m)
+}
+
+// AccountStatementService_ServiceDesc is the grpc.ServiceDesc for AccountStatementService service.
+// It's only intended for direct use with grpc.RegisterService,
+// and not to be introspected or modified (even as a copy)
+var AccountStatementService_ServiceDesc = grpc.ServiceDesc{
+	ServiceName: "grpc_crud.AccountStatementService",
+	HandlerType: (*AccountStatementServiceServer)(nil),
+	Methods: []grpc.MethodDesc{
+		{
+			MethodName: "GetStatement",
+			Handler:    _AccountStatementService_GetStatement_Handler,
+		},
+	},
+	Streams: []grpc.StreamDesc{
+		{
+			StreamName:    "StreamStatement",
+			Handler:       _AccountStatementService_StreamStatement_Handler,
+			ServerStreams: true,
+		},
+		{
+			StreamName:    "ExportStatement",
+			Handler:       _AccountStatementService_ExportStatement_Handler,
+			ServerStreams: true,
+		},
+	},
+	Metadata: "user_account.proto",
+}
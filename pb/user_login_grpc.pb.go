@@ -19,10 +19,12 @@ import (
 const _ = grpc.SupportPackageIsVersion7
 
 const (
-	LoginService_Register_FullMethodName     = "/grpc_crud.LoginService/Register"
-	LoginService_Login_FullMethodName        = "/grpc_crud.LoginService/Login"
-	LoginService_Logout_FullMethodName       = "/grpc_crud.LoginService/Logout"
-	LoginService_RefreshToken_FullMethodName = "/grpc_crud.LoginService/RefreshToken"
+	LoginService_Register_FullMethodName       = "/grpc_crud.LoginService/Register"
+	LoginService_Login_FullMethodName          = "/grpc_crud.LoginService/Login"
+	LoginService_Logout_FullMethodName         = "/grpc_crud.LoginService/Logout"
+	LoginService_RefreshToken_FullMethodName   = "/grpc_crud.LoginService/RefreshToken"
+	LoginService_UpdateUserRole_FullMethodName = "/grpc_crud.LoginService/UpdateUserRole"
+	LoginService_LinkCustomer_FullMethodName   = "/grpc_crud.LoginService/LinkCustomer"
 )
 
 // LoginServiceClient is the client API for LoginService service.
@@ -37,6 +39,10 @@ type LoginServiceClient interface {
 	Logout(ctx context.Context, in *UserLogoutRequest, opts ...grpc.CallOption) (*UserLogoutResponse, error)
 	// Refresh access token
 	RefreshToken(ctx context.Context, in *TokenRequest, opts ...grpc.CallOption) (*TokenResponse, error)
+	// Change the role of a user (admin only)
+	UpdateUserRole(ctx context.Context, in *UpdateUserRoleRequest, opts ...grpc.CallOption) (*UpdateUserRoleResponse, error)
+	// Link a login user to the bank customer it acts for
+	LinkCustomer(ctx context.Context, in *LinkCustomerRequest, opts ...grpc.CallOption) (*LinkCustomerResponse, error)
 }
 
 type loginServiceClient struct {
@@ -83,6 +89,24 @@ func (c *loginServiceClient) RefreshToken(ctx context.Context, in *TokenRequest,
 	return out, nil
 }
 
+func (c *loginServiceClient) UpdateUserRole(ctx context.Context, in *UpdateUserRoleRequest, opts ...grpc.CallOption) (*UpdateUserRoleResponse, error) {
+	out := new(UpdateUserRoleResponse)
+	err := c.cc.Invoke(ctx, LoginService_UpdateUserRole_FullMethodName, in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *loginServiceClient) LinkCustomer(ctx context.Context, in *LinkCustomerRequest, opts ...grpc.CallOption) (*LinkCustomerResponse, error) {
+	out := new(LinkCustomerResponse)
+	err := c.cc.Invoke(ctx, LoginService_LinkCustomer_FullMethodName, in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
 // LoginServiceServer is the server API for LoginService service.
 // All implementations must embed UnimplementedLoginServiceServer
 // for forward compatibility
@@ -95,6 +119,10 @@ type LoginServiceServer interface {
 	Logout(context.Context, *UserLogoutRequest) (*UserLogoutResponse, error)
 	// Refresh access token
 	RefreshToken(context.Context, *TokenRequest) (*TokenResponse, error)
+	// Change the role of a user (admin only)
+	UpdateUserRole(context.Context, *UpdateUserRoleRequest) (*UpdateUserRoleResponse, error)
+	// Link a login user to the bank customer it acts for
+	LinkCustomer(context.Context, *LinkCustomerRequest) (*LinkCustomerResponse, error)
 	mustEmbedUnimplementedLoginServiceServer()
 }
 
@@ -114,6 +142,12 @@ func (UnimplementedLoginServiceServer) Logout(context.Context, *UserLogoutReques
 func (UnimplementedLoginServiceServer) RefreshToken(context.Context, *TokenRequest) (*TokenResponse, error) {
 	return nil, status.Errorf(codes.Unimplemented, "method RefreshToken not implemented")
 }
+func (UnimplementedLoginServiceServer) UpdateUserRole(context.Context, *UpdateUserRoleRequest) (*UpdateUserRoleResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method UpdateUserRole not implemented")
+}
+func (UnimplementedLoginServiceServer) LinkCustomer(context.Context, *LinkCustomerRequest) (*LinkCustomerResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method LinkCustomer not implemented")
+}
 func (UnimplementedLoginServiceServer) mustEmbedUnimplementedLoginServiceServer() {}
 
 // UnsafeLoginServiceServer may be embedded to opt out of forward compatibility for this service.
@@ -199,6 +233,42 @@ func _LoginService_RefreshToken_Handler(srv interface{}, ctx context.Context, de
 	return interceptor(ctx, in, info, handler)
 }
 
+func _LoginService_UpdateUserRole_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(UpdateUserRoleRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(LoginServiceServer).UpdateUserRole(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: LoginService_UpdateUserRole_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(LoginServiceServer).UpdateUserRole(ctx, req.(*UpdateUserRoleRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _LoginService_LinkCustomer_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(LinkCustomerRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(LoginServiceServer).LinkCustomer(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: LoginService_LinkCustomer_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(LoginServiceServer).LinkCustomer(ctx, req.(*LinkCustomerRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
 // LoginService_ServiceDesc is the grpc.ServiceDesc for LoginService service.
 // It's only intended for direct use with grpc.RegisterService,
 // and not to be introspected or modified (even as a copy)
@@ -222,6 +292,14 @@ var LoginService_ServiceDesc = grpc.ServiceDesc{
 			MethodName: "RefreshToken",
 			Handler:    _LoginService_RefreshToken_Handler,
 		},
+		{
+			MethodName: "UpdateUserRole",
+			Handler:    _LoginService_UpdateUserRole_Handler,
+		},
+		{
+			MethodName: "LinkCustomer",
+			Handler:    _LoginService_LinkCustomer_Handler,
+		},
 	},
 	Streams:  []grpc.StreamDesc{},
 	Metadata: "user_login.proto",